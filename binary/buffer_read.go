@@ -1,22 +1,41 @@
 package binary
 
 import (
+	"io"
+	"time"
+
 	"github.com/webmafia/fast"
 )
 
-var _ Reader = (*BufferReader)(nil)
+var (
+	_ Reader        = (*BufferReader)(nil)
+	_ io.ReadSeeker = (*BufferReader)(nil)
+	_ io.ReaderAt   = (*BufferReader)(nil)
+)
 
+// A BufferReader reads binary data from a byte slice. Reads past the end never
+// panic; they return zero values and record a sticky error, available through Error.
 type BufferReader struct {
 	buf    []byte
 	cursor int
+	err    error
+	order  ByteOrder
 }
 
-func NewBufferReader(buf []byte) *BufferReader {
+// NewBufferReader creates a BufferReader reading from buf, and accepts an optional
+// byte order (defaults to LittleEndian).
+func NewBufferReader(buf []byte, order ...ByteOrder) *BufferReader {
 	return &BufferReader{
-		buf: buf,
+		buf:   buf,
+		order: byteOrder(order),
 	}
 }
 
+// Error returns the first error that occurred while reading, if any.
+func (b *BufferReader) Error() error {
+	return b.err
+}
+
 func (b *BufferReader) Len() int {
 	return b.Cap() - b.cursor
 }
@@ -25,28 +44,155 @@ func (b *BufferReader) Cap() int {
 	return len(b.buf)
 }
 
+// Reset rewinds the reader to the beginning and clears any error.
 func (b *BufferReader) Reset() {
 	b.cursor = 0
+	b.err = nil
+}
+
+// next returns the next n bytes and advances the cursor. If fewer than n bytes
+// remain, the error is recorded and nil is returned.
+func (b *BufferReader) next(n int) (p []byte) {
+	if n < 0 {
+		b.fail(ErrNegativeCount)
+		return nil
+	}
+
+	if n > len(b.buf)-b.cursor {
+		b.fail(io.ErrUnexpectedEOF)
+		return nil
+	}
+
+	p = b.buf[b.cursor : b.cursor+n]
+	b.cursor += n
+	return
+}
+
+// fail records the first error as a DecodeError at the current offset, and moves the
+// cursor to the end so that all subsequent reads fail as well.
+func (b *BufferReader) fail(err error) {
+	if b.err == nil {
+		b.err = &DecodeError{Offset: int64(b.cursor), Err: err}
+	}
+
+	b.cursor = len(b.buf)
+}
+
+// varint advances the cursor past a varint of n bytes, as returned by
+// binary.Varint or binary.Uvarint. It reports whether the varint was valid.
+func (b *BufferReader) varint(n int) bool {
+	if n > 0 {
+		b.cursor += n
+		return true
+	}
+
+	if n == 0 {
+		b.fail(io.ErrUnexpectedEOF)
+	} else {
+		b.fail(ErrVarintOverflow)
+	}
+
+	return false
 }
 
 func (b *BufferReader) Read(dst []byte) (n int, err error) {
+	if b.cursor >= len(b.buf) && len(dst) > 0 {
+		return 0, io.EOF
+	}
+
 	n = copy(dst, b.buf[b.cursor:])
 	b.cursor += n
 	return
 }
 
 func (b *BufferReader) ReadByte() (byte, error) {
+	if b.cursor >= len(b.buf) {
+		return 0, io.EOF
+	}
+
 	v := b.buf[b.cursor]
 	b.cursor++
 	return v, nil
 }
 
-func (b *BufferReader) ReadBytes(n int) (dst []byte) {
-	dst = b.buf[b.cursor : b.cursor+n]
-	b.cursor += n
-	return
+func (b *BufferReader) ReadBytes(n int) []byte {
+	return b.next(n)
+}
+
+// readRaw fills p with the next len(p) bytes, and reports whether it succeeded.
+func (b *BufferReader) readRaw(p []byte) bool {
+	src := b.next(len(p))
+	copy(p, src)
+	return src != nil || len(p) == 0
 }
 
 func (b *BufferReader) ReadString(n int) string {
 	return fast.BytesToString(b.ReadBytes(n))
 }
+
+// Read a type that implements Decoder
+func (b *BufferReader) ReadDec(v Decoder) error {
+	return v.Decode(b)
+}
+
+// ReadVal reads a value into ptr, which must be a pointer to a fixed-size scalar, a
+// time.Time or a time.Duration, or implement Decoder. Strings and byte slices are written
+// by WriteVal without a length, and can therefore not be read back by ReadVal.
+func (b *BufferReader) ReadVal(ptr any) error {
+	switch v := ptr.(type) {
+
+	case Decoder:
+		return b.ReadDec(v)
+
+	case *int:
+		*v = b.ReadInt()
+
+	case *int8:
+		*v = b.ReadInt8()
+
+	case *int16:
+		*v = b.ReadInt16()
+
+	case *int32:
+		*v = b.ReadInt32()
+
+	case *int64:
+		*v = b.ReadInt64()
+
+	case *uint:
+		*v = b.ReadUint()
+
+	case *uint8:
+		*v = b.ReadUint8()
+
+	case *uint16:
+		*v = b.ReadUint16()
+
+	case *uint32:
+		*v = b.ReadUint32()
+
+	case *uint64:
+		*v = b.ReadUint64()
+
+	case *float32:
+		*v = b.ReadFloat32()
+
+	case *float64:
+		*v = b.ReadFloat64()
+
+	case *bool:
+		*v = b.ReadBool()
+
+	case *time.Time:
+		*v = b.ReadTime()
+
+	case *time.Duration:
+		*v = b.ReadDuration()
+
+	default:
+		return ErrUnknownValue
+
+	}
+
+	return b.err
+}
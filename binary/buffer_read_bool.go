@@ -1,7 +1,5 @@
 package binary
 
 func (b *BufferReader) ReadBool() bool {
-	v := b.buf[b.cursor]
-	b.cursor++
-	return v != 0
+	return b.ReadUint8() != 0
 }
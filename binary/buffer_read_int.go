@@ -30,6 +30,10 @@ func (b *BufferReader) ReadInt() (v int) {
 
 func (b *BufferReader) ReadVarint() (v int64) {
 	v, n := binary.Varint(b.buf[b.cursor:])
-	b.cursor += n
+
+	if !b.varint(n) {
+		return 0
+	}
+
 	return
 }
@@ -0,0 +1,77 @@
+package binary
+
+import (
+	"fmt"
+	"io"
+)
+
+// Offset returns the number of bytes read so far.
+func (b *BufferReader) Offset() int {
+	return b.cursor
+}
+
+// Seek implements io.Seeker. Seeking before the start or beyond the end of the buffer is
+// rejected with ErrInvalidOffset. Once an error is recorded, reads keep failing and Seek
+// returns the error without moving the cursor, until the reader is reset.
+func (b *BufferReader) Seek(offset int64, whence int) (int64, error) {
+	if b.err != nil {
+		return int64(b.cursor), b.err
+	}
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += int64(b.cursor)
+	case io.SeekEnd:
+		offset += int64(len(b.buf))
+	default:
+		return 0, fmt.Errorf("%w: whence %d", ErrInvalidValue, whence)
+	}
+
+	if offset < 0 || offset > int64(len(b.buf)) {
+		return 0, fmt.Errorf("%w: %d of %d bytes", ErrInvalidOffset, offset, len(b.buf))
+	}
+
+	b.cursor = int(offset)
+	return offset, nil
+}
+
+// Skip advances the cursor n bytes. If fewer than n bytes remain, the error is recorded.
+func (b *BufferReader) Skip(n int) {
+	b.next(n)
+}
+
+// Peek returns the next n bytes without advancing the cursor, or nil if fewer than n
+// bytes remain. No error is recorded.
+func (b *BufferReader) Peek(n int) []byte {
+	if n < 0 || n > len(b.buf)-b.cursor {
+		return nil
+	}
+
+	return b.buf[b.cursor : b.cursor+n]
+}
+
+// Sub returns a reader of the next n bytes with the same byte order, and advances the
+// cursor past them. The child reader can't read beyond its n bytes. If fewer than n bytes
+// remain, the error is recorded and the child reader is empty.
+func (b *BufferReader) Sub(n int) *BufferReader {
+	p := b.next(n)
+	return NewBufferReader(p[:len(p):len(p)], b.order)
+}
+
+// ReadAt implements io.ReaderAt. It neither uses nor moves the cursor.
+func (b *BufferReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidOffset, off)
+	}
+
+	if off >= int64(len(b.buf)) {
+		return 0, io.EOF
+	}
+
+	if n = copy(p, b.buf[off:]); n < len(p) {
+		err = io.EOF
+	}
+
+	return
+}
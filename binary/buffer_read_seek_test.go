@@ -0,0 +1,97 @@
+package binary
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBufferReader_Seek(t *testing.T) {
+	r := NewBufferReader([]byte("0123456789"))
+
+	if p := r.Peek(3); string(p) != "012" || r.Offset() != 0 {
+		t.Fatalf("expected '012' at offset 0, got '%s' at %d", p, r.Offset())
+	}
+
+	r.Skip(2)
+
+	if off, err := r.Seek(3, io.SeekCurrent); err != nil || off != 5 {
+		t.Fatalf("expected offset 5, got %d (%v)", off, err)
+	}
+
+	if s := r.ReadString(2); s != "56" {
+		t.Errorf("expected '56', got '%s'", s)
+	}
+
+	if off, err := r.Seek(-1, io.SeekEnd); err != nil || off != 9 {
+		t.Fatalf("expected offset 9, got %d (%v)", off, err)
+	}
+
+	if p := r.Peek(2); p != nil {
+		t.Errorf("expected nil when peeking past the end, got '%s'", p)
+	}
+
+	if _, err := r.Seek(11, io.SeekStart); !errors.Is(err, ErrInvalidOffset) {
+		t.Errorf("expected ErrInvalidOffset, got %v", err)
+	}
+
+	if r.Error() != nil {
+		t.Errorf("expected no recorded error, got %v", r.Error())
+	}
+
+	r.Skip(2)
+
+	if !errors.Is(r.Error(), io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", r.Error())
+	}
+
+	// Seeking back mustn't make reads succeed while the error is recorded.
+	if _, err := r.Seek(0, io.SeekStart); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected the recorded error, got %v", err)
+	}
+
+	if v := r.ReadUint8(); v != 0 {
+		t.Errorf("expected a zero value after an error, got %d", v)
+	}
+
+	r.Reset()
+
+	if off, err := r.Seek(1, io.SeekStart); err != nil || off != 1 {
+		t.Errorf("expected offset 1 after a reset, got %d (%v)", off, err)
+	}
+}
+
+func TestBufferReader_Sub(t *testing.T) {
+	w := NewBufferWriter(64, BigEndian)
+	w.WriteUint16(0x0102)
+	w.WriteUint16(0x0304)
+	w.WriteUint8(5)
+
+	r := NewBufferReader(w.Bytes(), BigEndian)
+	sub := r.Sub(4)
+
+	if v := sub.ReadUint16(); v != 0x0102 {
+		t.Errorf("expected 0x0102, got %#x", v)
+	}
+
+	if v := sub.ReadUint32(); v != 0 || !errors.Is(sub.Error(), io.ErrUnexpectedEOF) {
+		t.Errorf("expected the child reader to be bounded, got %#x (%v)", v, sub.Error())
+	}
+
+	if v := r.ReadUint8(); v != 5 {
+		t.Errorf("expected 5, got %d", v)
+	}
+
+	if sub = r.Sub(1); sub.Len() != 0 || !errors.Is(r.Error(), io.ErrUnexpectedEOF) {
+		t.Errorf("expected an empty child and an error, got %d bytes (%v)", sub.Len(), r.Error())
+	}
+}
+
+func TestBufferReader_ReadAt(t *testing.T) {
+	data := []byte("0123456789")
+
+	if err := iotest.TestReader(NewBufferReader(data), data); err != nil {
+		t.Error(err)
+	}
+}
@@ -5,28 +5,48 @@ import (
 )
 
 func (b *BufferReader) ReadUint8() (v uint8) {
-	v = b.buf[b.cursor]
-	b.cursor++
+	if p := b.next(1); p != nil {
+		v = p[0]
+	}
+
 	return
 }
 
 func (b *BufferReader) ReadUint16() (v uint16) {
-	v = binary.LittleEndian.Uint16(b.buf[b.cursor:])
-	b.cursor += 2
+	if p := b.next(2); p != nil {
+		if b.order == BigEndian {
+			v = binary.BigEndian.Uint16(p)
+		} else {
+			v = binary.LittleEndian.Uint16(p)
+		}
+	}
+
 	return
 }
 
 // Write uint32
 func (b *BufferReader) ReadUint32() (v uint32) {
-	v = binary.LittleEndian.Uint32(b.buf[b.cursor:])
-	b.cursor += 4
+	if p := b.next(4); p != nil {
+		if b.order == BigEndian {
+			v = binary.BigEndian.Uint32(p)
+		} else {
+			v = binary.LittleEndian.Uint32(p)
+		}
+	}
+
 	return
 }
 
 // Write uint64
 func (b *BufferReader) ReadUint64() (v uint64) {
-	v = binary.LittleEndian.Uint64(b.buf[b.cursor:])
-	b.cursor += 8
+	if p := b.next(8); p != nil {
+		if b.order == BigEndian {
+			v = binary.BigEndian.Uint64(p)
+		} else {
+			v = binary.LittleEndian.Uint64(p)
+		}
+	}
+
 	return
 }
 
@@ -37,6 +57,10 @@ func (b *BufferReader) ReadUint() (v uint) {
 
 func (b *BufferReader) ReadUvarint() (v uint64) {
 	v, n := binary.Uvarint(b.buf[b.cursor:])
-	b.cursor += n
+
+	if !b.varint(n) {
+		return 0
+	}
+
 	return
 }
@@ -1,6 +1,9 @@
 package binary
 
 import (
+	"encoding"
+	"time"
+
 	"github.com/webmafia/fast"
 )
 
@@ -10,12 +13,16 @@ var _ Writer = (*BufferWriter)(nil)
 // It minimizes memory copying. The zero value is ready to use.
 // Do not copy a non-zero Buffer.
 type BufferWriter struct {
-	buf []byte
+	buf   []byte
+	order ByteOrder
 }
 
-func NewBufferWriter(cap int) *BufferWriter {
+// NewBufferWriter creates a BufferWriter with an initial capacity, and accepts an optional
+// byte order (defaults to LittleEndian).
+func NewBufferWriter(cap int, order ...ByteOrder) *BufferWriter {
 	return &BufferWriter{
-		buf: make([]byte, 0, cap),
+		buf:   make([]byte, 0, cap),
+		order: byteOrder(order),
 	}
 }
 
@@ -90,17 +97,52 @@ func (b *BufferWriter) WriteString(s string) (int, error) {
 	return len(s), nil
 }
 
-// Write a type that implements StringEncoder
+// WriteEnc writes a type that implements Encoder. If it also implements Sizer, the buffer
+// is grown at most once, before encoding.
 func (b *BufferWriter) WriteEnc(v Encoder) error {
+	if s, ok := v.(Sizer); ok {
+		if n := s.EncodedSize(); cap(b.buf)-len(b.buf) < n {
+			b.grow(n)
+		}
+	}
+
 	return v.Encode(b)
 }
 
+// WriteVal writes a scalar, time.Time, time.Duration or a value implementing Encoder,
+// fast.BinaryAppender or encoding.BinaryMarshaler. Strings, byte slices and binary
+// representations are written without a length.
 func (b *BufferWriter) WriteVal(val any) error {
 	switch v := val.(type) {
 
 	case Encoder:
 		return b.WriteEnc(v)
 
+	case time.Time:
+		return b.WriteTime(v)
+
+	case time.Duration:
+		return b.WriteDuration(v)
+
+	case fast.BinaryAppender:
+		buf, err := v.AppendBinary(b.buf)
+
+		if err == nil {
+			b.buf = buf
+		}
+
+		return err
+
+	case encoding.BinaryMarshaler:
+		buf, err := v.MarshalBinary()
+
+		if err != nil {
+			return err
+		}
+
+		_, err = b.Write(buf)
+		return err
+
 	case string:
 		_, err := b.WriteString(v)
 		return err
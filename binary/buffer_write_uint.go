@@ -12,19 +12,34 @@ func (b *BufferWriter) WriteUint8(v uint8) error {
 
 // Write uint16
 func (b *BufferWriter) WriteUint16(v uint16) error {
-	b.buf = binary.LittleEndian.AppendUint16(b.buf, v)
+	if b.order == BigEndian {
+		b.buf = binary.BigEndian.AppendUint16(b.buf, v)
+	} else {
+		b.buf = binary.LittleEndian.AppendUint16(b.buf, v)
+	}
+
 	return nil
 }
 
 // Write uint32
 func (b *BufferWriter) WriteUint32(v uint32) error {
-	b.buf = binary.LittleEndian.AppendUint32(b.buf, v)
+	if b.order == BigEndian {
+		b.buf = binary.BigEndian.AppendUint32(b.buf, v)
+	} else {
+		b.buf = binary.LittleEndian.AppendUint32(b.buf, v)
+	}
+
 	return nil
 }
 
 // Write uint64
 func (b *BufferWriter) WriteUint64(v uint64) error {
-	b.buf = binary.LittleEndian.AppendUint64(b.buf, v)
+	if b.order == BigEndian {
+		b.buf = binary.BigEndian.AppendUint64(b.buf, v)
+	} else {
+		b.buf = binary.LittleEndian.AppendUint64(b.buf, v)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,50 @@
+package binary
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"unsafe"
+
+	"github.com/webmafia/fast"
+)
+
+// nativeLittleEndian reports whether the host is little-endian.
+var nativeLittleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+
+// native reports whether o is the byte order of the host, which allows fixed-width
+// values to be copied as-is.
+func (o ByteOrder) native() bool {
+	return (o == LittleEndian) == nativeLittleEndian
+}
+
+// asBytes reinterprets the memory of s as bytes.
+func asBytes[T any](s []T) []byte {
+	return fast.SliceToSlice[T, byte](s, len(s)*int(unsafe.Sizeof(*new(T))))
+}
+
+// swap16 converts values between the host's byte order and o.
+func swap16(s []uint16, o ByteOrder) {
+	if !o.native() {
+		for i, v := range s {
+			s[i] = bits.ReverseBytes16(v)
+		}
+	}
+}
+
+// swap32 converts values between the host's byte order and o.
+func swap32(s []uint32, o ByteOrder) {
+	if !o.native() {
+		for i, v := range s {
+			s[i] = bits.ReverseBytes32(v)
+		}
+	}
+}
+
+// swap64 converts values between the host's byte order and o.
+func swap64(s []uint64, o ByteOrder) {
+	if !o.native() {
+		for i, v := range s {
+			s[i] = bits.ReverseBytes64(v)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package binary
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/webmafia/fast/ringbuf"
+)
+
+func TestBulk(t *testing.T) {
+	u32 := []uint32{1, 0xdeadbeef, 3}
+	i64 := []int64{-1, 1 << 40, 0}
+	f64 := []float64{1.5, -2.25, 1e300}
+
+	for _, order := range []ByteOrder{LittleEndian, BigEndian} {
+		t.Run(order.String(), func(t *testing.T) {
+			bulk := NewBufferWriter(64, order)
+			bulk.WriteUint32s(u32)
+			bulk.WriteInt64s(i64)
+			bulk.WriteFloat64s(f64)
+
+			single := NewBufferWriter(64, order)
+
+			for _, v := range u32 {
+				single.WriteUint32(v)
+			}
+
+			for _, v := range i64 {
+				single.WriteInt64(v)
+			}
+
+			for _, v := range f64 {
+				single.WriteFloat64(v)
+			}
+
+			if !bytes.Equal(bulk.Bytes(), single.Bytes()) {
+				t.Fatalf("expected %x, got %x", single.Bytes(), bulk.Bytes())
+			}
+
+			var buf bytes.Buffer
+			w := NewStreamWriter(&buf, order)
+			w.WriteUint32s(u32)
+			w.WriteInt64s(i64)
+			w.WriteFloat64s(f64)
+			w.Flush()
+
+			if !bytes.Equal(buf.Bytes(), single.Bytes()) {
+				t.Fatalf("expected %x, got %x", single.Bytes(), buf.Bytes())
+			}
+
+			readers := map[string]Reader{
+				"buffer": NewBufferReader(single.Bytes(), order),
+				"stream": NewStreamReader(bytes.NewReader(single.Bytes()), order),
+				"ring":   NewRingReader(ringbuf.NewReader(bytes.NewReader(single.Bytes())), order),
+			}
+
+			for name, r := range readers {
+				gotU32 := make([]uint32, len(u32))
+				gotI64 := make([]int64, len(i64))
+				gotF64 := make([]float64, len(f64))
+				r.ReadUint32s(gotU32)
+				r.ReadInt64s(gotI64)
+				r.ReadFloat64s(gotF64)
+
+				if err := r.Error(); err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+
+				if !slices.Equal(gotU32, u32) || !slices.Equal(gotI64, i64) || !slices.Equal(gotF64, f64) {
+					t.Errorf("%s: got %v %v %v", name, gotU32, gotI64, gotF64)
+				}
+			}
+		})
+	}
+}
+
+func TestBulk_Truncated(t *testing.T) {
+	r := NewBufferReader([]byte{1, 2, 3})
+	r.ReadUint32s(make([]uint32, 1))
+
+	if r.Error() == nil {
+		t.Error("expected an error")
+	}
+}
+
+func BenchmarkBufferWriter_WriteFloat64s(b *testing.B) {
+	v := make([]float64, 1024)
+	w := NewBufferWriter(len(v) * 8)
+	b.SetBytes(int64(len(v) * 8))
+
+	for i := 0; i < b.N; i++ {
+		w.Reset()
+		w.WriteFloat64s(v)
+	}
+}
+
+func BenchmarkBufferWriter_WriteFloat64(b *testing.B) {
+	v := make([]float64, 1024)
+	w := NewBufferWriter(len(v) * 8)
+	b.SetBytes(int64(len(v) * 8))
+
+	for i := 0; i < b.N; i++ {
+		w.Reset()
+
+		for _, f := range v {
+			w.WriteFloat64(f)
+		}
+	}
+}
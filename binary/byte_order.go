@@ -0,0 +1,26 @@
+package binary
+
+// ByteOrder specifies how fixed-width integers and floats are encoded. The zero
+// value is LittleEndian.
+type ByteOrder uint8
+
+const (
+	LittleEndian ByteOrder = iota
+	BigEndian
+)
+
+func (o ByteOrder) String() string {
+	if o == BigEndian {
+		return "BigEndian"
+	}
+
+	return "LittleEndian"
+}
+
+func byteOrder(order []ByteOrder) ByteOrder {
+	if len(order) > 0 {
+		return order[0]
+	}
+
+	return LittleEndian
+}
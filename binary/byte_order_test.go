@@ -0,0 +1,64 @@
+package binary
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestByteOrder(t *testing.T) {
+	expected := map[ByteOrder][]byte{
+		LittleEndian: {0x02, 0x01, 0x06, 0x05, 0x04, 0x03, 0x0e, 0x0d, 0x0c, 0x0b, 0x0a, 0x09, 0x08, 0x07},
+		BigEndian:    {0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e},
+	}
+
+	for order, exp := range expected {
+		var buf bytes.Buffer
+		writers := []Writer{NewBufferWriter(16, order), NewStreamWriter(&buf, order)}
+
+		for _, w := range writers {
+			w.WriteUint16(0x0102)
+			w.WriteUint32(0x03040506)
+			w.WriteUint64(0x0708090a0b0c0d0e)
+		}
+
+		writers[1].(*StreamWriter).Flush()
+
+		if b := writers[0].(*BufferWriter).Bytes(); !bytes.Equal(b, exp) {
+			t.Errorf("%s BufferWriter: expected %x, got %x", order, exp, b)
+		}
+
+		if b := buf.Bytes(); !bytes.Equal(b, exp) {
+			t.Errorf("%s StreamWriter: expected %x, got %x", order, exp, b)
+		}
+
+		for _, r := range []Reader{NewBufferReader(exp, order), NewStreamReader(bytes.NewReader(exp), order)} {
+			if v := r.ReadUint16(); v != 0x0102 {
+				t.Errorf("%s %T: expected %x, got %x", order, r, 0x0102, v)
+			}
+
+			if v := r.ReadUint32(); v != 0x03040506 {
+				t.Errorf("%s %T: expected %x, got %x", order, r, 0x03040506, v)
+			}
+
+			if v := r.ReadUint64(); v != 0x0708090a0b0c0d0e {
+				t.Errorf("%s %T: expected %x, got %x", order, r, uint64(0x0708090a0b0c0d0e), v)
+			}
+		}
+	}
+}
+
+func BenchmarkBufferWriter_WriteUint64(b *testing.B) {
+	for _, order := range []ByteOrder{LittleEndian, BigEndian} {
+		b.Run(order.String(), func(b *testing.B) {
+			w := NewBufferWriter(8*1024, order)
+
+			for i := range b.N {
+				if i%1024 == 0 {
+					w.Reset()
+				}
+
+				w.WriteUint64(uint64(i))
+			}
+		})
+	}
+}
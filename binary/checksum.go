@@ -0,0 +1,246 @@
+package binary
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// NewCRC32C returns a CRC-32 hash using the Castagnoli polynomial, which is the default
+// checksum of ChecksumWriter and ChecksumReader.
+func NewCRC32C() hash.Hash32 {
+	return crc32.New(castagnoli)
+}
+
+// A ChecksumError is returned when a checksum doesn't match its trailer. It matches
+// ErrChecksumMismatch with errors.Is.
+type ChecksumError struct {
+	Expected []byte // The trailer, which is shorter than a checksum if the data was truncated
+	Actual   []byte // The checksum of the data
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s: expected %x, got %x", ErrChecksumMismatch, e.Expected, e.Actual)
+}
+
+func (e *ChecksumError) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+// A ChecksumWriter is a StreamWriter that keeps a running checksum over everything
+// written, and appends it as a trailer on Close.
+type ChecksumWriter struct {
+	*StreamWriter
+	dst     checksumSink
+	trailer []byte // unwritten part of the trailer, once Close has been called
+	closed  bool
+}
+
+// NewChecksumWriter creates a ChecksumWriter writing to w, e.g. a BufferWriter or a file.
+// The checksum defaults to CRC32C if h is nil, but any hash (e.g. xxhash) can be used.
+// It accepts an optional byte order (defaults to LittleEndian).
+func NewChecksumWriter(w io.Writer, h hash.Hash, order ...ByteOrder) *ChecksumWriter {
+	if h == nil {
+		h = NewCRC32C()
+	}
+
+	c := &ChecksumWriter{
+		dst: checksumSink{
+			w:    w,
+			hash: h,
+		},
+	}
+
+	// Data is hashed in chunks as the buffer is flushed.
+	c.StreamWriter = NewStreamWriter(&c.dst, order...)
+	return c
+}
+
+// Reset discards any unflushed data, resets the checksum, and resets c to write to w.
+func (c *ChecksumWriter) Reset(w io.Writer) {
+	c.dst.w = w
+	c.dst.hash.Reset()
+	c.trailer = nil
+	c.closed = false
+	c.StreamWriter.Reset(&c.dst)
+}
+
+// Sum flushes the buffer and returns the checksum of everything written so far.
+func (c *ChecksumWriter) Sum() ([]byte, error) {
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+
+	return c.dst.hash.Sum(nil), nil
+}
+
+// Close flushes the buffer and writes the checksum trailer. If it fails, it can be retried
+// like Flush, and once it has succeeded any later call is a no-op and any write fails with
+// ErrClosed, until c is reset. The underlying io.Writer is not closed.
+func (c *ChecksumWriter) Close() (err error) {
+	if c.closed {
+		return
+	}
+
+	if c.trailer == nil {
+		if c.trailer, err = c.Sum(); err != nil {
+			return
+		}
+	}
+
+	for len(c.trailer) > 0 {
+		var n int
+		n, err = c.dst.w.Write(c.trailer)
+		c.trailer = c.trailer[n:]
+
+		if err != nil {
+			return
+		}
+	}
+
+	c.closed = true
+	c.StreamWriter.err = ErrClosed
+	return
+}
+
+// checksumSink writes to w, and hashes the bytes that w accepted. A short write is retried
+// by the StreamWriter on the next flush, and must not be hashed twice.
+type checksumSink struct {
+	w    io.Writer
+	hash hash.Hash
+}
+
+func (s *checksumSink) Write(p []byte) (n int, err error) {
+	n, err = s.w.Write(p)
+	s.hash.Write(p[:n])
+	return
+}
+
+// A ChecksumReader is a StreamReader that keeps a running checksum over everything read,
+// and verifies it against the trailer written by a ChecksumWriter. The trailer itself is
+// never returned by any read.
+type ChecksumReader struct {
+	*StreamReader
+	src checksumSource
+}
+
+// NewChecksumReader creates a ChecksumReader reading from r, with the same hash as the
+// writer (defaults to CRC32C if h is nil). It accepts an optional byte order (defaults
+// to LittleEndian).
+func NewChecksumReader(r io.Reader, h hash.Hash, order ...ByteOrder) *ChecksumReader {
+	if h == nil {
+		h = NewCRC32C()
+	}
+
+	c := &ChecksumReader{
+		src: checksumSource{
+			r:    r,
+			hash: h,
+			tail: make([]byte, 0, h.Size()),
+		},
+	}
+
+	c.StreamReader = NewStreamReader(&c.src, order...)
+	return c
+}
+
+// Reset resets the checksum, and resets c to read from r.
+func (c *ChecksumReader) Reset(r io.Reader) {
+	c.src.reset(r)
+	c.StreamReader.Reset(&c.src)
+}
+
+// Verify discards any unread data and verifies the checksum of the whole stream. It
+// returns a ChecksumError if the checksum doesn't match the trailer.
+func (c *ChecksumReader) Verify() (err error) {
+	if _, err = io.Copy(io.Discard, c.StreamReader); err != nil {
+		return
+	}
+
+	if c.src.err != io.EOF {
+		return c.src.err
+	}
+
+	return nil
+}
+
+// VerifyChecksum verifies the checksum trailer of data, and returns the data without
+// the trailer, or a ChecksumError if it doesn't match. The checksum defaults to CRC32C if
+// h is nil.
+func VerifyChecksum(data []byte, h hash.Hash) ([]byte, error) {
+	if h == nil {
+		h = NewCRC32C()
+	}
+
+	n := max(0, len(data)-h.Size())
+
+	h.Reset()
+	h.Write(data[:n])
+
+	if sum := h.Sum(nil); !bytes.Equal(sum, data[n:]) {
+		return nil, &ChecksumError{Expected: bytes.Clone(data[n:]), Actual: sum}
+	}
+
+	return data[:n], nil
+}
+
+// A checksumSource hashes and returns everything from an io.Reader except the trailer,
+// which is withheld until the end of the stream and then verified.
+type checksumSource struct {
+	r    io.Reader
+	hash hash.Hash
+	tail []byte // withheld bytes, at most hash.Size()
+	buf  []byte
+	err  error
+}
+
+func (s *checksumSource) reset(r io.Reader) {
+	s.r = r
+	s.hash.Reset()
+	s.tail = s.tail[:0]
+	s.err = nil
+}
+
+func (s *checksumSource) Read(p []byte) (n int, err error) {
+	size := s.hash.Size()
+
+	for n == 0 && s.err == nil && len(p) > 0 {
+		if cap(s.buf) < size+len(p) {
+			s.buf = make([]byte, size+len(p))
+		}
+
+		buf := s.buf[:size+len(p)]
+		m := copy(buf, s.tail)
+		k, err := s.r.Read(buf[m:])
+		m += k
+
+		// Everything but the last size bytes is payload.
+		if out := m - size; out > 0 {
+			n = copy(p, buf[:out])
+			s.hash.Write(p[:n])
+			s.tail = append(s.tail[:0], buf[out:m]...)
+		} else {
+			s.tail = append(s.tail[:0], buf[:m]...)
+		}
+
+		if err == io.EOF {
+			if sum := s.hash.Sum(nil); bytes.Equal(sum, s.tail) {
+				s.err = io.EOF
+			} else {
+				s.err = &ChecksumError{Expected: bytes.Clone(s.tail), Actual: sum}
+			}
+		} else if err != nil {
+			s.err = err
+		}
+	}
+
+	if n > 0 {
+		return n, nil
+	}
+
+	return 0, s.err
+}
@@ -0,0 +1,138 @@
+package binary
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	crc32c := func() hash.Hash { return NewCRC32C() }
+
+	for _, newHash := range []func() hash.Hash{crc32c, sha256.New} {
+		bw := NewBufferWriter(64)
+		w := NewChecksumWriter(bw, newHash())
+
+		for i := range 1000 {
+			w.WriteUvarint(uint64(i))
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := VerifyChecksum(bw.Bytes(), newHash()); err != nil {
+			t.Fatal(err)
+		}
+
+		r := NewChecksumReader(bytes.NewReader(bw.Bytes()), newHash())
+
+		for i := range 1000 {
+			if v := r.ReadUvarint(); v != uint64(i) {
+				t.Fatalf("expected %d, got %d", i, v)
+			}
+		}
+
+		if err := r.Verify(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Corrupt a single byte.
+		bw.Bytes()[100]++
+		trailer := bw.Bytes()[bw.Len()-newHash().Size():]
+
+		_, err := VerifyChecksum(bw.Bytes(), newHash())
+		checkChecksumError(t, err, trailer)
+
+		err = NewChecksumReader(bytes.NewReader(bw.Bytes()), newHash()).Verify()
+		checkChecksumError(t, err, trailer)
+
+		if _, err := VerifyChecksum(trailer[:2], newHash()); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("expected ErrChecksumMismatch for truncated data, got %v", err)
+		}
+	}
+}
+
+func checkChecksumError(t *testing.T, err error, trailer []byte) {
+	t.Helper()
+
+	var ce *ChecksumError
+
+	if !errors.Is(err, ErrChecksumMismatch) || !errors.As(err, &ce) {
+		t.Fatalf("expected a ChecksumError, got %v", err)
+	}
+
+	if !bytes.Equal(ce.Expected, trailer) || len(ce.Actual) != len(trailer) || bytes.Equal(ce.Actual, trailer) {
+		t.Errorf("expected trailer %x and a different checksum, got %x and %x", trailer, ce.Expected, ce.Actual)
+	}
+}
+
+func TestChecksumWriter_WriteAfterClose(t *testing.T) {
+	bw := NewBufferWriter(64)
+	w := NewChecksumWriter(bw, nil)
+	w.WriteString("foo")
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.WriteUint8(1); err != ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+
+	if _, err := w.WriteString("bar"); err != ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Errorf("expected a repeated Close to be a no-op, got %v", err)
+	}
+
+	if data, err := VerifyChecksum(bw.Bytes(), nil); err != nil || string(data) != "foo" {
+		t.Errorf("expected 'foo' with a valid checksum, got '%s' (%v)", data, err)
+	}
+
+	// A reset writer can be written again.
+	bw.Reset()
+	w.Reset(bw)
+
+	if err := w.WriteUint8(1); err != nil {
+		t.Errorf("expected no error after a reset, got %v", err)
+	}
+}
+
+// shortWriter accepts at most n bytes per write.
+type shortWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n, _ := w.Buffer.Write(p[:w.n])
+		return n, io.ErrShortWrite
+	}
+
+	return w.Buffer.Write(p)
+}
+
+func TestChecksumWriter_ShortWrite(t *testing.T) {
+	dst := &shortWriter{n: 3}
+	w := NewChecksumWriter(dst, nil)
+	w.WriteString("hello, world")
+
+	// Retry until everything, including the trailer, has been written.
+	for w.Close() != nil {
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := VerifyChecksum(dst.Bytes(), nil); err != nil {
+		t.Errorf("expected a valid checksum with a single trailer, got %v (%x)", err, dst.Bytes())
+	}
+}
@@ -0,0 +1,288 @@
+package binary
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/webmafia/fast"
+)
+
+// ColumnEncoding specifies how an integer column is encoded by WriteInt64Column and
+// WriteUint64Column. The encoding is stored in the column, so readers don't need to know it.
+type ColumnEncoding uint8
+
+const (
+	// ColumnDelta stores the first value followed by the zigzag varint difference between
+	// each value and the previous one. It suits counters and sorted IDs.
+	ColumnDelta ColumnEncoding = iota
+
+	// ColumnDeltaOfDelta stores the first value and delta, followed by the zigzag varint
+	// difference between consecutive deltas. Evenly spaced values such as timestamps are
+	// encoded in one byte each.
+	ColumnDeltaOfDelta
+
+	// ColumnBitPack stores the smallest value (the frame of reference) and a bit width,
+	// followed by each value's offset from the smallest value packed into that many bits.
+	// It suits small values in no particular order.
+	ColumnBitPack
+)
+
+func (e ColumnEncoding) String() string {
+	switch e {
+	case ColumnDelta:
+		return "Delta"
+	case ColumnDeltaOfDelta:
+		return "DeltaOfDelta"
+	case ColumnBitPack:
+		return "BitPack"
+	}
+
+	return fmt.Sprintf("ColumnEncoding(%d)", uint8(e))
+}
+
+// WriteInt64Column writes an uvarint count, the encoding and all values in v.
+func WriteInt64Column(w Writer, v []int64, enc ColumnEncoding) error {
+	return writeColumn(w, fast.SliceToSlice[int64, uint64](v, len(v)), enc, true)
+}
+
+// WriteUint64Column writes an uvarint count, the encoding and all values in v.
+func WriteUint64Column(w Writer, v []uint64, enc ColumnEncoding) error {
+	return writeColumn(w, v, enc, false)
+}
+
+// ReadInt64Column reads a column written by WriteInt64Column. A count larger than maxLen is
+// rejected with ErrTooLarge. An empty column is returned as nil.
+func ReadInt64Column(r Reader, maxLen int) ([]int64, error) {
+	v, err := readColumn(r, maxLen, true)
+	return fast.SliceToSlice[uint64, int64](v, len(v)), err
+}
+
+// ReadUint64Column reads a column written by WriteUint64Column. A count larger than maxLen
+// is rejected with ErrTooLarge. An empty column is returned as nil.
+func ReadUint64Column(r Reader, maxLen int) ([]uint64, error) {
+	return readColumn(r, maxLen, false)
+}
+
+// All arithmetic is done on uint64, where wrapping makes deltas exact for both signed and
+// unsigned values. Signedness only affects how the first value is stored, and how values
+// are ordered when finding the frame of reference.
+func writeColumn(w Writer, v []uint64, enc ColumnEncoding, signed bool) (err error) {
+	if err = w.WriteUvarint(uint64(len(v))); err != nil {
+		return
+	}
+
+	if err = w.WriteUint8(uint8(enc)); err != nil {
+		return
+	}
+
+	if len(v) == 0 {
+		return
+	}
+
+	switch enc {
+
+	case ColumnDelta:
+		if err = writeFirst(w, v[0], signed); err != nil {
+			return
+		}
+
+		for i := 1; i < len(v); i++ {
+			if err = w.WriteVarint(int64(v[i] - v[i-1])); err != nil {
+				return
+			}
+		}
+
+	case ColumnDeltaOfDelta:
+		if err = writeFirst(w, v[0], signed); err != nil {
+			return
+		}
+
+		var delta uint64
+
+		for i := 1; i < len(v); i++ {
+			d := v[i] - v[i-1]
+
+			if err = w.WriteVarint(int64(d - delta)); err != nil {
+				return
+			}
+
+			delta = d
+		}
+
+	case ColumnBitPack:
+		return writeBitPacked(w, v, signed)
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownValue, enc)
+	}
+
+	return
+}
+
+func writeBitPacked(w Writer, v []uint64, signed bool) (err error) {
+	minKey, maxKey := ^uint64(0), uint64(0)
+
+	for _, x := range v {
+		k := orderKey(x, signed)
+		minKey = min(minKey, k)
+		maxKey = max(maxKey, k)
+	}
+
+	base := orderKey(minKey, signed)
+	width := uint(bits.Len64(maxKey - minKey))
+
+	if err = writeFirst(w, base, signed); err != nil {
+		return
+	}
+
+	if err = w.WriteUint8(uint8(width)); err != nil || width == 0 {
+		return
+	}
+
+	var (
+		acc   uint64
+		nbits uint
+	)
+
+	for _, x := range v {
+		x -= base
+		acc |= x << nbits
+
+		if nbits+width < 64 {
+			nbits += width
+			continue
+		}
+
+		if err = w.WriteUint64(acc); err != nil {
+			return
+		}
+
+		acc = x >> (64 - nbits)
+		nbits = nbits + width - 64
+	}
+
+	if nbits > 0 {
+		err = w.WriteUint64(acc)
+	}
+
+	return
+}
+
+func readColumn(r Reader, maxLen int, signed bool) (v []uint64, err error) {
+	n, err := readCount(r, maxLen)
+
+	if err != nil {
+		return
+	}
+
+	enc := ColumnEncoding(r.ReadUint8())
+
+	if err = r.Error(); err != nil || n == 0 {
+		return
+	}
+
+	v = make([]uint64, 0, PreallocLen[uint64](n))
+	prev := readFirst(r, signed)
+
+	switch enc {
+
+	case ColumnDelta:
+		v = append(v, prev)
+
+		for i := 1; i < n && r.Error() == nil; i++ {
+			prev += uint64(r.ReadVarint())
+			v = append(v, prev)
+		}
+
+	case ColumnDeltaOfDelta:
+		v = append(v, prev)
+
+		var delta uint64
+
+		for i := 1; i < n && r.Error() == nil; i++ {
+			delta += uint64(r.ReadVarint())
+			prev += delta
+			v = append(v, prev)
+		}
+
+	case ColumnBitPack:
+		return readBitPacked(r, v, n, prev)
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownValue, enc)
+	}
+
+	if err = r.Error(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+func readBitPacked(r Reader, v []uint64, n int, base uint64) ([]uint64, error) {
+	width := uint(r.ReadUint8())
+
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+
+	if width > 64 {
+		return nil, fmt.Errorf("%w: bit width %d", ErrInvalidValue, width)
+	}
+
+	var (
+		acc   uint64
+		nbits uint
+		mask  = uint64(1)<<width - 1
+	)
+
+	for range n {
+		var x uint64
+
+		if width <= nbits {
+			x = acc & mask
+			acc >>= width
+			nbits -= width
+		} else {
+			next := r.ReadUint64()
+
+			if err := r.Error(); err != nil {
+				return nil, err
+			}
+
+			x = (acc | next<<nbits) & mask
+			acc = next >> (width - nbits)
+			nbits = 64 - (width - nbits)
+		}
+
+		v = append(v, base+x)
+	}
+
+	return v, nil
+}
+
+func writeFirst(w Writer, v uint64, signed bool) error {
+	if signed {
+		return w.WriteVarint(int64(v))
+	}
+
+	return w.WriteUvarint(v)
+}
+
+func readFirst(r Reader, signed bool) uint64 {
+	if signed {
+		return uint64(r.ReadVarint())
+	}
+
+	return r.ReadUvarint()
+}
+
+// orderKey maps signed values onto unsigned ones with the same ordering. It is its own
+// inverse.
+func orderKey(v uint64, signed bool) uint64 {
+	if signed {
+		return v ^ 1<<63
+	}
+
+	return v
+}
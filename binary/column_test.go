@@ -0,0 +1,101 @@
+package binary
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestColumn(t *testing.T) {
+	columns := [][]int64{
+		nil,
+		{0},
+		{1700000000000, 1700000001000, 1700000002000, 1700000003000, 1700000004500},
+		{3, -7, 12, 0, 5, 5, 5},
+		{math.MinInt64, math.MaxInt64, 0, -1},
+	}
+
+	for _, enc := range []ColumnEncoding{ColumnDelta, ColumnDeltaOfDelta, ColumnBitPack} {
+		t.Run(enc.String(), func(t *testing.T) {
+			for _, col := range columns {
+				w := NewBufferWriter(64)
+
+				if err := WriteInt64Column(w, col, enc); err != nil {
+					t.Fatal(err)
+				}
+
+				got, err := ReadInt64Column(NewBufferReader(w.Bytes()), len(col))
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !slices.Equal(got, col) {
+					t.Errorf("expected %v, got %v", col, got)
+				}
+
+				ucol := make([]uint64, len(col))
+
+				for i, v := range col {
+					ucol[i] = uint64(v)
+				}
+
+				w.Reset()
+
+				if err := WriteUint64Column(w, ucol, enc); err != nil {
+					t.Fatal(err)
+				}
+
+				ugot, err := ReadUint64Column(NewBufferReader(w.Bytes()), len(col))
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !slices.Equal(ugot, ucol) {
+					t.Errorf("expected %v, got %v", ucol, ugot)
+				}
+			}
+		})
+	}
+}
+
+func TestColumn_Size(t *testing.T) {
+	ts := make([]int64, 1000)
+
+	for i := range ts {
+		ts[i] = 1700000000000 + int64(i)*1000
+	}
+
+	w := NewBufferWriter(64)
+	WriteInt64Column(w, ts, ColumnDeltaOfDelta)
+
+	// Count, encoding, first value, first delta, and one byte per remaining value.
+	if n := w.Len(); n != 2+1+6+2+998 {
+		t.Errorf("expected %d bytes, got %d", 2+1+6+2+998, n)
+	}
+
+	counters := make([]uint64, 1000)
+
+	for i := range counters {
+		counters[i] = uint64(100 + i%16)
+	}
+
+	w.Reset()
+	WriteUint64Column(w, counters, ColumnBitPack)
+
+	// Count, encoding, base, width, and 1000 values of 4 bits in 63 words.
+	if n := w.Len(); n != 2+1+1+1+63*8 {
+		t.Errorf("expected %d bytes, got %d", 2+1+1+1+63*8, n)
+	}
+}
+
+func TestColumn_TooLarge(t *testing.T) {
+	w := NewBufferWriter(64)
+	WriteUint64Column(w, []uint64{1, 2, 3}, ColumnDelta)
+
+	if _, err := ReadUint64Column(NewBufferReader(w.Bytes()), 2); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package binary
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A DecodeError is an error that occurred while decoding, together with the byte offset
+// where it occurred and the path of the field being decoded, e.g. "Order.Items[3].Price".
+// Readers record DecodeErrors with an offset, and decoders prepend their field to the path
+// with FieldError, IndexError and KeyError as the error is returned.
+type DecodeError struct {
+	Offset int64  // Byte offset in the stream, or -1 if unknown
+	Path   string // Field path, or empty if unknown
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+
+	if e.Path != "" {
+		b.WriteString(" in ")
+		b.WriteString(e.Path)
+	}
+
+	if e.Offset >= 0 {
+		b.WriteString(" at offset ")
+		b.WriteString(strconv.FormatInt(e.Offset, 10))
+	}
+
+	return b.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError returns err with the field name prepended to its path. A nil error is
+// returned as-is.
+func FieldError(err error, name string) error {
+	return withPath(err, name)
+}
+
+// IndexError returns err with the slice or array index i prepended to its path. A nil
+// error is returned as-is.
+func IndexError(err error, i int) error {
+	return withPath(err, "["+strconv.Itoa(i)+"]")
+}
+
+// KeyError returns err with the map key prepended to its path. A nil error is returned
+// as-is.
+func KeyError(err error, key any) error {
+	if s, ok := key.(string); ok {
+		return withPath(err, "["+strconv.Quote(s)+"]")
+	}
+
+	return withPath(err, fmt.Sprintf("[%v]", key))
+}
+
+func withPath(err error, segment string) error {
+	if err == nil {
+		return nil
+	}
+
+	de, ok := err.(*DecodeError)
+
+	if ok {
+		cp := *de
+		de = &cp
+	} else {
+		de = &DecodeError{Offset: -1, Err: err}
+	}
+
+	switch {
+	case de.Path == "":
+		de.Path = segment
+	case de.Path[0] == '[':
+		de.Path = segment + de.Path
+	default:
+		de.Path = segment + "." + de.Path
+	}
+
+	return de
+}
@@ -0,0 +1,99 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestDecodeError(t *testing.T) {
+	src := testOrder()
+	w := NewBufferWriter(64)
+
+	if err := Marshal(w, &src); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cut the data in the middle of the second item's price.
+	cut := bytes.Index(w.Bytes(), []byte("\x03bar")) + 4 + 4
+	data := w.Bytes()[:cut]
+
+	readers := map[string]Reader{
+		"buffer": NewBufferReader(data),
+		"stream": NewStreamReader(bytes.NewReader(data)),
+	}
+
+	for name, r := range readers {
+		var dst marshalOrder
+		err := Unmarshal(r, &dst)
+
+		var de *DecodeError
+
+		if !errors.As(err, &de) {
+			t.Fatalf("%s: expected a DecodeError, got %v", name, err)
+		}
+
+		if de.Path != "marshalOrder.Items[1].Price" {
+			t.Errorf("%s: expected path %q, got %q", name, "marshalOrder.Items[1].Price", de.Path)
+		}
+
+		if de.Offset < 0 || de.Offset > int64(cut) {
+			t.Errorf("%s: unexpected offset %d", name, de.Offset)
+		}
+
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%s: expected an EOF error, got %v", name, err)
+		}
+	}
+}
+
+func TestDecodeError_Path(t *testing.T) {
+	err := FieldError(KeyError(IndexError(io.EOF, 3), "foo"), "Items")
+	exp := `EOF in Items["foo"][3]`
+
+	if err.Error() != exp {
+		t.Errorf("expected %q, got %q", exp, err.Error())
+	}
+
+	if FieldError(nil, "Items") != nil {
+		t.Error("expected a nil error to stay nil")
+	}
+}
+
+func TestStreamReader_StickyError(t *testing.T) {
+	r := NewStreamReader(bytes.NewReader([]byte{1, 2}))
+	r.ReadUint32()
+	err := r.Error()
+
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if _, err2 := r.ReadByte(); err2 != err {
+		t.Errorf("expected the sticky error %v, got %v", err, err2)
+	}
+
+	if r.Offset() != 2 {
+		t.Errorf("expected offset 2, got %d", r.Offset())
+	}
+}
+
+func TestStreamReader_ReadBytes_Hostile(t *testing.T) {
+	r := NewStreamReader(bytes.NewReader([]byte("foo")))
+
+	if b := r.ReadBytes(maxInt); b != nil || !errors.Is(r.Error(), io.ErrUnexpectedEOF) {
+		t.Errorf("expected nil and io.ErrUnexpectedEOF, got %d bytes and %v", len(b), r.Error())
+	}
+
+	// Marshal reads lengths from the wire, which must not be trusted either.
+	w := NewBufferWriter(16)
+	w.WriteUvarint(uint64(maxInt))
+	w.WriteString("foo")
+
+	var s string
+
+	if err := Unmarshal(NewStreamReader(bytes.NewReader(w.Bytes())), &s); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
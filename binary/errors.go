@@ -3,6 +3,13 @@ package binary
 import "errors"
 
 var (
-	ErrUnknownValue  = errors.New("unknown value")
-	ErrNegativeCount = errors.New("negative count")
+	ErrUnknownValue     = errors.New("unknown value")
+	ErrInvalidValue     = errors.New("invalid value")
+	ErrNegativeCount    = errors.New("negative count")
+	ErrTooLarge         = errors.New("length too large")
+	ErrVarintOverflow   = errors.New("varint overflows a 64-bit integer")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrInvalidOffset    = errors.New("invalid offset")
+	ErrTooDeep          = errors.New("nested too deep")
+	ErrClosed           = errors.New("write after close")
 )
@@ -0,0 +1,128 @@
+package binary
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// A FrameWriter writes length-prefixed frames, each consisting of an uvarint length
+// followed by the payload.
+type FrameWriter struct {
+	w       Writer
+	buf     *BufferWriter
+	maxSize int
+}
+
+// NewFrameWriter creates a FrameWriter writing frames of at most maxSize bytes to w,
+// and accepts an optional byte order of the payload (defaults to LittleEndian).
+func NewFrameWriter(w Writer, maxSize int, order ...ByteOrder) *FrameWriter {
+	return &FrameWriter{
+		w:       w,
+		buf:     NewBufferWriter(min(maxSize, 4096), order...),
+		maxSize: maxSize,
+	}
+}
+
+// Reset resets the FrameWriter to write to w.
+func (f *FrameWriter) Reset(w Writer) {
+	f.w = w
+	f.buf.Reset()
+}
+
+// WriteFrame encodes v as a single frame. Nothing is written if the encoded payload
+// exceeds the maximum frame size.
+func (f *FrameWriter) WriteFrame(v Encoder) (err error) {
+	f.buf.Reset()
+
+	if err = v.Encode(f.buf); err != nil {
+		return
+	}
+
+	return f.WriteFrameBytes(f.buf.Bytes())
+}
+
+// WriteFrameBytes writes an already encoded payload as a single frame.
+func (f *FrameWriter) WriteFrameBytes(p []byte) (err error) {
+	if len(p) > f.maxSize {
+		return fmt.Errorf("%w: frame of %d bytes exceeds %d", ErrTooLarge, len(p), f.maxSize)
+	}
+
+	if err = f.w.WriteUvarint(uint64(len(p))); err != nil {
+		return
+	}
+
+	_, err = f.w.Write(p)
+	return
+}
+
+// A FrameReader reads length-prefixed frames written by a FrameWriter.
+type FrameReader struct {
+	r       Reader
+	frame   BufferReader
+	maxSize int
+}
+
+// NewFrameReader creates a FrameReader reading frames of at most maxSize bytes from r,
+// and accepts an optional byte order of the payload (defaults to LittleEndian).
+func NewFrameReader(r Reader, maxSize int, order ...ByteOrder) *FrameReader {
+	return &FrameReader{
+		r:       r,
+		frame:   BufferReader{order: byteOrder(order)},
+		maxSize: maxSize,
+	}
+}
+
+// Reset resets the FrameReader to read from r.
+func (f *FrameReader) Reset(r Reader) {
+	f.r = r
+	f.frame.buf = nil
+	f.frame.Reset()
+}
+
+// Next reads the next frame and returns a Reader bounded to its payload, so that a
+// decoder can never read past the frame. The Reader is only valid until the next
+// call to Next. At the end of the stream, io.EOF is returned.
+func (f *FrameReader) Next() (Reader, error) {
+	l, err := binary.ReadUvarint(f.r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if l > uint64(f.maxSize) {
+		return nil, fmt.Errorf("%w: frame of %d bytes exceeds %d", ErrTooLarge, l, f.maxSize)
+	}
+
+	n := int(l)
+	buf := f.r.ReadBytes(n)
+
+	if len(buf) < n {
+		if err = f.r.Error(); err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	f.frame.buf = buf
+	f.frame.Reset()
+
+	return &f.frame, nil
+}
+
+// ReadFrame reads the next frame and decodes it into v. Any read past the end of
+// the frame is returned as an error.
+func (f *FrameReader) ReadFrame(v Decoder) (err error) {
+	r, err := f.Next()
+
+	if err != nil {
+		return
+	}
+
+	if err = v.Decode(r); err != nil {
+		return
+	}
+
+	return r.Error()
+}
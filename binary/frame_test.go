@@ -0,0 +1,68 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+type frameMessage struct {
+	ID   uint32
+	Name string
+}
+
+func (m *frameMessage) Encode(w Writer) error {
+	w.WriteUint32(m.ID)
+	w.WriteUvarint(uint64(len(m.Name)))
+	_, err := w.WriteString(m.Name)
+	return err
+}
+
+func (m *frameMessage) Decode(r Reader) error {
+	m.ID = r.ReadUint32()
+	m.Name = r.ReadString(int(r.ReadUvarint()))
+	return nil
+}
+
+func TestFrame(t *testing.T) {
+	var buf bytes.Buffer
+	sw := NewStreamWriter(&buf)
+	fw := NewFrameWriter(sw, 16)
+
+	for _, m := range []frameMessage{{1, "foo"}, {2, "bar"}} {
+		if err := fw.WriteFrame(&m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := fw.WriteFrame(&frameMessage{3, "this is way too long"}); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+
+	// A frame shorter than what the decoder expects.
+	fw.WriteFrameBytes([]byte{1, 2})
+	sw.Flush()
+
+	fr := NewFrameReader(NewStreamReader(&buf), 16)
+
+	for _, exp := range []frameMessage{{1, "foo"}, {2, "bar"}} {
+		var m frameMessage
+
+		if err := fr.ReadFrame(&m); err != nil {
+			t.Fatal(err)
+		}
+
+		if m != exp {
+			t.Errorf("expected %v, got %v", exp, m)
+		}
+	}
+
+	if err := fr.ReadFrame(new(frameMessage)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	if _, err := fr.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
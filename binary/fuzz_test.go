@@ -2,6 +2,8 @@ package binary
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"testing"
 )
 
@@ -57,3 +59,89 @@ func FuzzStreamReader(f *testing.F) {
 		}
 	})
 }
+
+func FuzzBufferReader_Truncated(f *testing.F) {
+	f.Add([]byte{}, uint8(0))
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, uint8(3))
+	f.Add([]byte("foobar"), uint8(7))
+
+	f.Fuzz(func(t *testing.T, data []byte, op uint8) {
+		r := NewBufferReader(data)
+
+		// Keep reading until the reader is exhausted or fails. This must never panic.
+		for i := 0; r.Error() == nil && i <= len(data); i++ {
+			switch (int(op) + i) % 10 {
+			case 0:
+				r.ReadUint8()
+			case 1:
+				r.ReadUint16()
+			case 2:
+				r.ReadUint32()
+			case 3:
+				r.ReadUint64()
+			case 4:
+				r.ReadVarint()
+			case 5:
+				r.ReadUvarint()
+			case 6:
+				r.ReadBool()
+			case 7:
+				r.ReadString(int(r.ReadUvarint()))
+			case 8:
+				r.ReadFloat64()
+			case 9:
+				r.ReadBytes(int(op) - 128)
+			}
+		}
+
+		if err := r.Error(); err != nil {
+			if !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, ErrVarintOverflow) && !errors.Is(err, ErrNegativeCount) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if r.Len() != 0 {
+				t.Fatalf("expected the reader to be exhausted after an error, got %d bytes left", r.Len())
+			}
+
+			if v := r.ReadUint64(); v != 0 {
+				t.Fatalf("expected zero value after an error, got %d", v)
+			}
+		}
+	})
+}
+
+func FuzzColumn(f *testing.F) {
+	f.Add([]byte{0, 0, 0, 0, 0, 0, 0, 0}, uint8(0))
+	f.Add([]byte{0xe8, 0x03, 0, 0, 0, 0, 0, 0, 0xd0, 0x07, 0, 0, 0, 0, 0, 0}, uint8(1))
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0, 0, 0, 0, 0, 0, 0}, uint8(2))
+
+	f.Fuzz(func(t *testing.T, data []byte, enc uint8) {
+		col := make([]int64, len(data)/8)
+		NewBufferReader(data).ReadInt64s(col)
+
+		w := NewBufferWriter(64)
+
+		if err := WriteInt64Column(w, col, ColumnEncoding(enc%3)); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ReadInt64Column(NewBufferReader(w.Bytes()), len(col))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got) != len(col) {
+			t.Fatalf("expected %d values, got %d", len(col), len(got))
+		}
+
+		for i := range col {
+			if got[i] != col[i] {
+				t.Fatalf("expected %d at %d, got %d", col[i], i, got[i])
+			}
+		}
+
+		// Arbitrary input must never panic.
+		ReadUint64Column(NewBufferReader(data), 1<<16)
+	})
+}
@@ -1,11 +1,15 @@
 package binary
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 type Reader interface {
 	io.Reader
 	io.ByteReader
 
+	Error() error
 	Len() int
 	Cap() int
 	ReadBytes(n int) []byte
@@ -25,6 +29,18 @@ type Reader interface {
 	ReadVarint() int64
 	ReadUvarint() uint64
 	ReadBool() bool
+	ReadTime() time.Time
+	ReadDuration() time.Duration
+	ReadUint16s(dst []uint16)
+	ReadInt16s(dst []int16)
+	ReadUint32s(dst []uint32)
+	ReadInt32s(dst []int32)
+	ReadUint64s(dst []uint64)
+	ReadInt64s(dst []int64)
+	ReadFloat32s(dst []float32)
+	ReadFloat64s(dst []float64)
+	ReadDec(v Decoder) error
+	ReadVal(ptr any) error
 }
 
 type Writer interface {
@@ -47,6 +63,16 @@ type Writer interface {
 	WriteVarint(v int64) error
 	WriteUvarint(v uint64) error
 	WriteBool(v bool) error
+	WriteTime(t time.Time) error
+	WriteDuration(d time.Duration) error
+	WriteUint16s(v []uint16) error
+	WriteInt16s(v []int16) error
+	WriteUint32s(v []uint32) error
+	WriteInt32s(v []int32) error
+	WriteUint64s(v []uint64) error
+	WriteInt64s(v []int64) error
+	WriteFloat32s(v []float32) error
+	WriteFloat64s(v []float64) error
 	WriteEnc(v Encoder) error
 	WriteVal(val any) error
 }
@@ -54,3 +80,7 @@ type Writer interface {
 type Encoder interface {
 	Encode(w Writer) error
 }
+
+type Decoder interface {
+	Decode(r Reader) error
+}
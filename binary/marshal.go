@@ -0,0 +1,801 @@
+package binary
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/webmafia/fast"
+)
+
+// Encode and decode functions are passed the nesting depth of pointers, slices and maps, so
+// that neither a cyclic value nor a malicious input can overflow the stack.
+type encodeFunc func(w Writer, p unsafe.Pointer, depth int) error
+type decodeFunc func(r Reader, p unsafe.Pointer, depth int) error
+
+// MaxDepth is the maximum nesting of pointers, slices and maps that Marshal and Unmarshal
+// handle.
+const MaxDepth = 1024
+
+// A codec is the compiled encode/decode plan of a single type.
+type codec struct {
+	enc encodeFunc
+	dec decodeFunc
+}
+
+var (
+	codecs   sync.Map // map[reflect.Type]*codec
+	codecsMu sync.Mutex
+
+	encoderType           = reflect.TypeFor[Encoder]()
+	decoderType           = reflect.TypeFor[Decoder]()
+	binaryMarshalerType   = reflect.TypeFor[encoding.BinaryMarshaler]()
+	binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+	timeType              = reflect.TypeFor[time.Time]()
+	durationType          = reflect.TypeFor[time.Duration]()
+)
+
+// Marshal encodes v into w. Structs, arrays, slices, maps and pointers are walked
+// recursively, and the resulting plan is cached per type. Types implementing
+// Encoder are encoded with their Encode method, and time.Time and time.Duration
+// values as by WriteTime and WriteDuration.
+//
+// Strings, byte slices, slices and maps are prefixed with their length as an uvarint,
+// and pointers with a presence byte. Empty slices and maps are decoded as nil.
+// Struct fields that are unexported or tagged with `bin:"-"` are skipped. A struct
+// with only unexported fields must implement both encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler, and is encoded as length-prefixed bytes; otherwise it
+// can't be marshaled.
+//
+// Pointers, slices and maps may be nested at most MaxDepth levels, both when encoding
+// and decoding, or ErrTooDeep is returned. This also stops a cyclic value from being
+// encoded forever.
+//
+// Pass a pointer to avoid copying v.
+func Marshal(w Writer, v any) error {
+	if enc, ok := v.(Encoder); ok {
+		return enc.Encode(w)
+	}
+
+	rv := reflect.ValueOf(v)
+
+	if !rv.IsValid() {
+		return ErrInvalidValue
+	}
+
+	if rv.Kind() != reflect.Pointer {
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		rv = ptr
+	} else if rv.IsNil() {
+		return ErrInvalidValue
+	}
+
+	c, err := codecOf(rv.Type().Elem())
+
+	if err != nil {
+		return err
+	}
+
+	return c.enc(w, rv.UnsafePointer(), 0)
+}
+
+// Unmarshal decodes data encoded by Marshal from r into v, which must be a non-nil pointer.
+// Types implementing Decoder are decoded with their Decode method. Decode errors are
+// returned as a DecodeError, with a path starting at the name of the decoded type.
+func Unmarshal(r Reader, v any) (err error) {
+	if dec, ok := v.(Decoder); ok {
+		if err = dec.Decode(r); err == nil {
+			err = r.Error()
+		}
+
+		return rootError(err, reflect.TypeOf(v))
+	}
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrInvalidValue
+	}
+
+	c, err := codecOf(rv.Type().Elem())
+
+	if err != nil {
+		return err
+	}
+
+	if err = c.dec(r, rv.UnsafePointer(), 0); err == nil {
+		err = r.Error()
+	}
+
+	return rootError(err, rv.Type())
+}
+
+// rootError prepends the name of the decoded type to the path of err.
+func rootError(err error, t reflect.Type) error {
+	if err == nil {
+		return nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Name() == "" {
+		return err
+	}
+
+	return FieldError(err, t.Name())
+}
+
+func codecOf(t reflect.Type) (*codec, error) {
+	if c, ok := codecs.Load(t); ok {
+		return c.(*codec), nil
+	}
+
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	// Codecs are only published once the whole type graph has compiled, so that
+	// no other goroutine can observe a half-built (e.g. recursive) plan.
+	building := make(map[reflect.Type]*codec)
+	c, err := compile(t, building)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for t, c := range building {
+		codecs.Store(t, c)
+	}
+
+	return c, nil
+}
+
+func compile(t reflect.Type, building map[reflect.Type]*codec) (c *codec, err error) {
+	if c, ok := codecs.Load(t); ok {
+		return c.(*codec), nil
+	}
+
+	if c, ok := building[t]; ok {
+		return c, nil
+	}
+
+	c = new(codec)
+	building[t] = c
+
+	var (
+		enc encodeFunc
+		dec decodeFunc
+	)
+
+	// Types implementing Encoder and/or Decoder are handled by their own methods.
+	if t.Kind() != reflect.Pointer {
+		enc, dec = methodsOf(t)
+	}
+
+	if enc == nil || dec == nil {
+		if err = compileKind(c, t, building); err != nil {
+			return
+		}
+	}
+
+	if enc != nil {
+		c.enc = enc
+	}
+
+	if dec != nil {
+		c.dec = dec
+	}
+
+	return
+}
+
+func methodsOf(t reflect.Type) (enc encodeFunc, dec decodeFunc) {
+	pt := reflect.PointerTo(t)
+
+	if pt.Implements(encoderType) {
+		enc = func(w Writer, p unsafe.Pointer, _ int) error {
+			return reflect.NewAt(t, p).Interface().(Encoder).Encode(w)
+		}
+	}
+
+	if pt.Implements(decoderType) {
+		dec = func(r Reader, p unsafe.Pointer, _ int) error {
+			return reflect.NewAt(t, p).Interface().(Decoder).Decode(r)
+		}
+	}
+
+	return
+}
+
+func compileKind(c *codec, t reflect.Type, building map[reflect.Type]*codec) (err error) {
+	switch t {
+
+	case timeType:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteTime(*(*time.Time)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*time.Time)(p) = r.ReadTime(); return nil }
+		return
+
+	case durationType:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteDuration(*(*time.Duration)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*time.Duration)(p) = r.ReadDuration(); return nil }
+		return
+
+	}
+
+	switch t.Kind() {
+
+	case reflect.Bool:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteBool(*(*bool)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*bool)(p) = r.ReadBool(); return nil }
+
+	case reflect.Int:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteInt(*(*int)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*int)(p) = r.ReadInt(); return nil }
+
+	case reflect.Int8:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteInt8(*(*int8)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*int8)(p) = r.ReadInt8(); return nil }
+
+	case reflect.Int16:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteInt16(*(*int16)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*int16)(p) = r.ReadInt16(); return nil }
+
+	case reflect.Int32:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteInt32(*(*int32)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*int32)(p) = r.ReadInt32(); return nil }
+
+	case reflect.Int64:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteInt64(*(*int64)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*int64)(p) = r.ReadInt64(); return nil }
+
+	case reflect.Uint:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteUint(*(*uint)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*uint)(p) = r.ReadUint(); return nil }
+
+	case reflect.Uint8:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteUint8(*(*uint8)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*uint8)(p) = r.ReadUint8(); return nil }
+
+	case reflect.Uint16:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteUint16(*(*uint16)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*uint16)(p) = r.ReadUint16(); return nil }
+
+	case reflect.Uint32:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteUint32(*(*uint32)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*uint32)(p) = r.ReadUint32(); return nil }
+
+	case reflect.Uint64:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteUint64(*(*uint64)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*uint64)(p) = r.ReadUint64(); return nil }
+
+	case reflect.Uintptr:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteUint64(uint64(*(*uintptr)(p))) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*uintptr)(p) = uintptr(r.ReadUint64()); return nil }
+
+	case reflect.Float32:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteFloat32(*(*float32)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*float32)(p) = r.ReadFloat32(); return nil }
+
+	case reflect.Float64:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error { return w.WriteFloat64(*(*float64)(p)) }
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error { *(*float64)(p) = r.ReadFloat64(); return nil }
+
+	case reflect.Complex64:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error {
+			v := *(*complex64)(p)
+			if err := w.WriteFloat32(real(v)); err != nil {
+				return err
+			}
+
+			return w.WriteFloat32(imag(v))
+		}
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error {
+			*(*complex64)(p) = complex(r.ReadFloat32(), r.ReadFloat32())
+			return nil
+		}
+
+	case reflect.Complex128:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) error {
+			v := *(*complex128)(p)
+			if err := w.WriteFloat64(real(v)); err != nil {
+				return err
+			}
+
+			return w.WriteFloat64(imag(v))
+		}
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error {
+			*(*complex128)(p) = complex(r.ReadFloat64(), r.ReadFloat64())
+			return nil
+		}
+
+	case reflect.String:
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) (err error) {
+			s := *(*string)(p)
+
+			if err = w.WriteUvarint(uint64(len(s))); err != nil {
+				return
+			}
+
+			_, err = w.WriteString(s)
+			return
+		}
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) error {
+			b, err := ReadLenBytes(r)
+			*(*string)(p) = fast.BytesToString(b)
+			return err
+		}
+
+	case reflect.Slice:
+		err = compileSlice(c, t, building)
+
+	case reflect.Array:
+		err = compileArray(c, t, building)
+
+	case reflect.Map:
+		err = compileMap(c, t, building)
+
+	case reflect.Pointer:
+		err = compilePointer(c, t, building)
+
+	case reflect.Struct:
+		err = compileStruct(c, t, building)
+
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnknownValue, t)
+
+	}
+
+	return
+}
+
+// ReadLen reads an uvarint length prefix, as written by Marshal before strings, byte
+// slices, slices and maps.
+func ReadLen(r Reader) (n int, err error) {
+	l := r.ReadUvarint()
+
+	if err = r.Error(); err != nil {
+		return
+	}
+
+	if l > uint64(maxInt) {
+		return 0, ErrTooLarge
+	}
+
+	return int(l), nil
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// ReadLenBytes reads an uvarint length prefix, and that many bytes into a newly allocated
+// slice, as written by Marshal for strings and byte slices. The slice is grown as data
+// arrives, so that a malicious length can't allocate more memory than the data backing it.
+// An empty slice is returned as nil.
+func ReadLenBytes(r Reader) (b []byte, err error) {
+	n, err := ReadLen(r)
+
+	if err != nil || n == 0 {
+		return
+	}
+
+	return readFull(r, n)
+}
+
+// readFull reads exactly n bytes from r into a newly allocated slice. The slice is grown as
+// data arrives, so that a malicious length can't allocate more memory than the data backing
+// it. If r ends before n bytes, the bytes read so far are returned with the error from
+// io.ReadFull.
+func readFull(r io.Reader, n int) (b []byte, err error) {
+	b = make([]byte, 0, preallocLen(n, 1))
+
+	for len(b) < n && err == nil {
+		if len(b) == cap(b) {
+			b = slices.Grow(b, min(n-len(b), cap(b)))
+		}
+
+		var k int
+		k, err = io.ReadFull(r, b[len(b):min(n, cap(b))])
+		b = b[:len(b)+k]
+	}
+
+	return
+}
+
+// maxPrealloc limits how many bytes are allocated upfront for a decoded length.
+const maxPrealloc = 64 << 10
+
+func preallocLen(n int, size uintptr) int {
+	if size == 0 {
+		return n
+	}
+
+	return min(n, max(1, maxPrealloc/int(size)))
+}
+
+// PreallocLen returns how many elements of type T that should be allocated upfront when
+// decoding n elements. Any remaining elements should be appended as they are decoded, so
+// that a malicious length can't allocate more memory than the data backing it.
+func PreallocLen[T any](n int) int {
+	return preallocLen(n, unsafe.Sizeof(*new(T)))
+}
+
+func compileSlice(c *codec, t reflect.Type, building map[reflect.Type]*codec) error {
+	et := t.Elem()
+
+	if pt := reflect.PointerTo(et); et.Kind() == reflect.Uint8 && !pt.Implements(encoderType) && !pt.Implements(decoderType) {
+		c.enc = func(w Writer, p unsafe.Pointer, _ int) (err error) {
+			b := *(*[]byte)(p)
+
+			if err = w.WriteUvarint(uint64(len(b))); err != nil {
+				return
+			}
+
+			_, err = w.Write(b)
+			return
+		}
+		c.dec = func(r Reader, p unsafe.Pointer, _ int) (err error) {
+			*(*[]byte)(p), err = ReadLenBytes(r)
+			return
+		}
+
+		return nil
+	}
+
+	elem, err := compile(et, building)
+
+	if err != nil {
+		return err
+	}
+
+	size := et.Size()
+
+	c.enc = func(w Writer, p unsafe.Pointer, depth int) (err error) {
+		// All slices share the same header layout.
+		if depth >= MaxDepth {
+			return ErrTooDeep
+		}
+
+		s := *(*[]byte)(p)
+		data := unsafe.Pointer(unsafe.SliceData(s))
+
+		if err = w.WriteUvarint(uint64(len(s))); err != nil {
+			return
+		}
+
+		for i := range len(s) {
+			if err = elem.enc(w, unsafe.Add(data, uintptr(i)*size), depth+1); err != nil {
+				return
+			}
+		}
+
+		return
+	}
+
+	c.dec = func(r Reader, p unsafe.Pointer, depth int) (err error) {
+		if depth >= MaxDepth {
+			return ErrTooDeep
+		}
+
+		n, err := ReadLen(r)
+
+		if err != nil {
+			return
+		}
+
+		if n == 0 {
+			reflect.NewAt(t, p).Elem().SetZero()
+			return
+		}
+
+		s := reflect.NewAt(t, p).Elem()
+		s.Set(reflect.MakeSlice(t, 0, preallocLen(n, size)))
+
+		for i := range n {
+			if i == s.Cap() {
+				s.Grow(min(n-i, i))
+			}
+
+			s.SetLen(i + 1)
+
+			if err = elem.dec(r, unsafe.Add(s.UnsafePointer(), uintptr(i)*size), depth+1); err == nil {
+				err = r.Error()
+			}
+
+			if err != nil {
+				return IndexError(err, i)
+			}
+		}
+
+		return
+	}
+
+	return nil
+}
+
+func compileArray(c *codec, t reflect.Type, building map[reflect.Type]*codec) error {
+	elem, err := compile(t.Elem(), building)
+
+	if err != nil {
+		return err
+	}
+
+	size := t.Elem().Size()
+	n := t.Len()
+
+	c.enc = func(w Writer, p unsafe.Pointer, depth int) (err error) {
+		for i := range n {
+			if err = elem.enc(w, unsafe.Add(p, uintptr(i)*size), depth); err != nil {
+				return
+			}
+		}
+
+		return
+	}
+
+	c.dec = func(r Reader, p unsafe.Pointer, depth int) (err error) {
+		for i := range n {
+			if err = elem.dec(r, unsafe.Add(p, uintptr(i)*size), depth); err == nil {
+				err = r.Error()
+			}
+
+			if err != nil {
+				return IndexError(err, i)
+			}
+		}
+
+		return
+	}
+
+	return nil
+}
+
+func compileMap(c *codec, t reflect.Type, building map[reflect.Type]*codec) error {
+	kt, vt := t.Key(), t.Elem()
+	key, err := compile(kt, building)
+
+	if err != nil {
+		return err
+	}
+
+	val, err := compile(vt, building)
+
+	if err != nil {
+		return err
+	}
+
+	c.enc = func(w Writer, p unsafe.Pointer, depth int) (err error) {
+		if depth >= MaxDepth {
+			return ErrTooDeep
+		}
+
+		m := reflect.NewAt(t, p).Elem()
+
+		if err = w.WriteUvarint(uint64(m.Len())); err != nil || m.Len() == 0 {
+			return
+		}
+
+		k := reflect.New(kt).Elem()
+		v := reflect.New(vt).Elem()
+		iter := m.MapRange()
+
+		for iter.Next() {
+			k.SetIterKey(iter)
+			v.SetIterValue(iter)
+
+			if err = key.enc(w, k.Addr().UnsafePointer(), depth+1); err != nil {
+				return
+			}
+
+			if err = val.enc(w, v.Addr().UnsafePointer(), depth+1); err != nil {
+				return
+			}
+		}
+
+		return
+	}
+
+	c.dec = func(r Reader, p unsafe.Pointer, depth int) (err error) {
+		if depth >= MaxDepth {
+			return ErrTooDeep
+		}
+
+		n, err := ReadLen(r)
+
+		if err != nil {
+			return
+		}
+
+		if n == 0 {
+			reflect.NewAt(t, p).Elem().SetZero()
+			return
+		}
+
+		m := reflect.MakeMapWithSize(t, preallocLen(n, kt.Size()+vt.Size()))
+		k := reflect.New(kt).Elem()
+		v := reflect.New(vt).Elem()
+
+		for range n {
+			k.SetZero()
+			v.SetZero()
+
+			if err = key.dec(r, k.Addr().UnsafePointer(), depth+1); err == nil {
+				err = r.Error()
+			}
+
+			if err != nil {
+				return
+			}
+
+			if err = val.dec(r, v.Addr().UnsafePointer(), depth+1); err == nil {
+				err = r.Error()
+			}
+
+			if err != nil {
+				return KeyError(err, k.Interface())
+			}
+
+			m.SetMapIndex(k, v)
+		}
+
+		reflect.NewAt(t, p).Elem().Set(m)
+		return
+	}
+
+	return nil
+}
+
+func compilePointer(c *codec, t reflect.Type, building map[reflect.Type]*codec) error {
+	et := t.Elem()
+	elem, err := compile(et, building)
+
+	if err != nil {
+		return err
+	}
+
+	c.enc = func(w Writer, p unsafe.Pointer, depth int) (err error) {
+		ptr := *(*unsafe.Pointer)(p)
+
+		if err = w.WriteBool(ptr != nil); err != nil || ptr == nil {
+			return
+		}
+
+		if depth >= MaxDepth {
+			return ErrTooDeep
+		}
+
+		return elem.enc(w, ptr, depth+1)
+	}
+
+	c.dec = func(r Reader, p unsafe.Pointer, depth int) (err error) {
+		if !r.ReadBool() {
+			*(*unsafe.Pointer)(p) = nil
+			return
+		}
+
+		if depth >= MaxDepth {
+			return ErrTooDeep
+		}
+
+		ptr := reflect.New(et).UnsafePointer()
+
+		if err = elem.dec(r, ptr, depth+1); err != nil {
+			return
+		}
+
+		*(*unsafe.Pointer)(p) = ptr
+		return
+	}
+
+	return nil
+}
+
+type structField struct {
+	name   string
+	offset uintptr
+	codec  *codec
+}
+
+func compileStruct(c *codec, t reflect.Type, building map[reflect.Type]*codec) error {
+	fields := make([]structField, 0, t.NumField())
+	unexported := false
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		if !f.IsExported() {
+			unexported = true
+			continue
+		}
+
+		if f.Tag.Get("bin") == "-" {
+			continue
+		}
+
+		fc, err := compile(f.Type, building)
+
+		if err != nil {
+			return err
+		}
+
+		fields = append(fields, structField{
+			name:   f.Name,
+			offset: f.Offset,
+			codec:  fc,
+		})
+	}
+
+	// A struct with only unexported fields (e.g. netip.Addr) would otherwise silently be
+	// encoded as nothing.
+	if len(fields) == 0 && unexported {
+		return compileOpaque(c, t)
+	}
+
+	c.enc = func(w Writer, p unsafe.Pointer, depth int) (err error) {
+		for i := range fields {
+			if err = fields[i].codec.enc(w, unsafe.Add(p, fields[i].offset), depth); err != nil {
+				return
+			}
+		}
+
+		return
+	}
+
+	c.dec = func(r Reader, p unsafe.Pointer, depth int) (err error) {
+		for i := range fields {
+			if err = fields[i].codec.dec(r, unsafe.Add(p, fields[i].offset), depth); err == nil {
+				err = r.Error()
+			}
+
+			if err != nil {
+				return FieldError(err, fields[i].name)
+			}
+		}
+
+		return
+	}
+
+	return nil
+}
+
+// compileOpaque compiles a struct without exported fields, which can only be encoded through
+// its encoding.BinaryMarshaler and encoding.BinaryUnmarshaler methods.
+func compileOpaque(c *codec, t reflect.Type) error {
+	pt := reflect.PointerTo(t)
+
+	if !pt.Implements(binaryMarshalerType) || !pt.Implements(binaryUnmarshalerType) {
+		return fmt.Errorf("%w: %s has no exported fields", ErrUnknownValue, t)
+	}
+
+	c.enc = func(w Writer, p unsafe.Pointer, _ int) (err error) {
+		b, err := reflect.NewAt(t, p).Interface().(encoding.BinaryMarshaler).MarshalBinary()
+
+		if err != nil {
+			return
+		}
+
+		if err = w.WriteUvarint(uint64(len(b))); err != nil {
+			return
+		}
+
+		_, err = w.Write(b)
+		return
+	}
+
+	c.dec = func(r Reader, p unsafe.Pointer, _ int) error {
+		b, err := ReadLenBytes(r)
+
+		if err != nil {
+			return err
+		}
+
+		return reflect.NewAt(t, p).Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(b)
+	}
+
+	return nil
+}
@@ -0,0 +1,324 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"net/netip"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type marshalItem struct {
+	Name  string
+	Price float64
+	Tags  []string
+}
+
+type marshalOrder struct {
+	ID       uint64
+	Customer *string
+	Items    []marshalItem
+	Counts   map[string]int32
+	Hash     [4]byte
+	Raw      []byte
+	Next     *marshalOrder
+	Ignored  int `bin:"-"`
+	internal int
+}
+
+func testOrder() marshalOrder {
+	customer := "räksmörgås"
+
+	return marshalOrder{
+		ID:       123,
+		Customer: &customer,
+		Items: []marshalItem{
+			{Name: "foo", Price: 1.5, Tags: []string{"a", "b"}},
+			{Name: "bar", Price: -2},
+		},
+		Counts: map[string]int32{"foo": 1, "bar": -2},
+		Hash:   [4]byte{1, 2, 3, 4},
+		Raw:    []byte("raw"),
+		Next:   &marshalOrder{ID: 456},
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	src := testOrder()
+	src.Ignored = 789
+	src.internal = 789
+
+	w := NewBufferWriter(64)
+
+	if err := Marshal(w, &src); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst marshalOrder
+
+	if err := Unmarshal(NewBufferReader(w.Bytes()), &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	src.Ignored = 0
+	src.internal = 0
+
+	if !reflect.DeepEqual(src, dst) {
+		t.Errorf("expected %+v, got %+v", src, dst)
+	}
+}
+
+func TestMarshal_Stream(t *testing.T) {
+	src := testOrder()
+
+	var buf bytes.Buffer
+
+	w := NewStreamWriter(&buf)
+
+	if err := Marshal(w, src); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst marshalOrder
+
+	if err := Unmarshal(NewStreamReader(&buf), &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(src, dst) {
+		t.Errorf("expected %+v, got %+v", src, dst)
+	}
+}
+
+func TestMarshal_Unsupported(t *testing.T) {
+	w := NewBufferWriter(64)
+
+	if err := Marshal(w, struct{ C chan int }{}); err == nil {
+		t.Error("expected error")
+	}
+
+	if err := Marshal(w, struct{ S sync.Mutex }{}); !errors.Is(err, ErrUnknownValue) {
+		t.Errorf("expected ErrUnknownValue for a struct without exported fields, got %v", err)
+	}
+
+	if err := Unmarshal(NewBufferReader(nil), marshalOrder{}); err != ErrInvalidValue {
+		t.Errorf("expected ErrInvalidValue, got %v", err)
+	}
+}
+
+type marshalList struct {
+	Next *marshalList
+}
+
+type marshalTree []marshalTree
+
+func TestMarshal_Depth(t *testing.T) {
+	// Every 0x01 is a present pointer, which used to recurse until the stack overflowed.
+	if err := Unmarshal(NewBufferReader(bytes.Repeat([]byte{1}, 8<<20)), &marshalList{}); !errors.Is(err, ErrTooDeep) {
+		t.Errorf("expected ErrTooDeep for nested pointers, got %v", err)
+	}
+
+	if err := Unmarshal(NewBufferReader(bytes.Repeat([]byte{1}, 8<<20)), new(marshalTree)); !errors.Is(err, ErrTooDeep) {
+		t.Errorf("expected ErrTooDeep for nested slices, got %v", err)
+	}
+
+	cyclic := &marshalList{}
+	cyclic.Next = cyclic
+
+	if err := Marshal(NewBufferWriter(64), cyclic); !errors.Is(err, ErrTooDeep) {
+		t.Errorf("expected ErrTooDeep for a cyclic value, got %v", err)
+	}
+
+	// The maximum depth itself round trips.
+	var src marshalList
+
+	for range MaxDepth {
+		next := src
+		src = marshalList{Next: &next}
+	}
+
+	w := NewBufferWriter(MaxDepth + 1)
+
+	if err := Marshal(w, &src); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst marshalList
+
+	if err := Unmarshal(NewBufferReader(w.Bytes()), &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(dst, src) {
+		t.Error("expected the nested list to round trip")
+	}
+
+	next := src
+	src = marshalList{Next: &next}
+
+	if err := Marshal(NewBufferWriter(64), &src); !errors.Is(err, ErrTooDeep) {
+		t.Errorf("expected ErrTooDeep beyond MaxDepth, got %v", err)
+	}
+}
+
+func TestMarshal_BinaryMarshaler(t *testing.T) {
+	type host struct {
+		Addr netip.Addr
+		Port uint16
+	}
+
+	src := host{Addr: netip.MustParseAddr("fe80::1%eth0"), Port: 80}
+	w := NewBufferWriter(64)
+
+	if err := Marshal(w, &src); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst host
+
+	if err := Unmarshal(NewBufferReader(w.Bytes()), &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst != src {
+		t.Errorf("expected %+v, got %+v", src, dst)
+	}
+}
+
+func TestMarshal_Time(t *testing.T) {
+	type event struct {
+		At  time.Time
+		Dur time.Duration
+	}
+
+	src := event{At: time.Date(2024, 2, 29, 12, 30, 0, 123, time.UTC), Dur: 1500 * time.Millisecond}
+	w := NewBufferWriter(64)
+
+	if err := Marshal(w, &src); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := SizeTime(src.At) + SizeDuration(src.Dur); w.Len() != exp {
+		t.Errorf("expected WriteTime and WriteDuration encodings of %d bytes, got %d", exp, w.Len())
+	}
+
+	var dst event
+
+	if err := Unmarshal(NewBufferReader(w.Bytes()), &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst != src {
+		t.Errorf("expected %+v, got %+v", src, dst)
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	src := testOrder()
+	w := NewBufferWriter(256)
+	b.ResetTimer()
+
+	for range b.N {
+		w.Reset()
+		_ = Marshal(w, &src)
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	src := testOrder()
+	w := NewBufferWriter(256)
+	_ = Marshal(w, &src)
+	r := NewBufferReader(w.Bytes())
+	b.ResetTimer()
+
+	for range b.N {
+		var dst marshalOrder
+		r.Reset()
+		_ = Unmarshal(r, &dst)
+	}
+}
+
+type marshalPoint struct {
+	X, Y int16
+	C    chan int
+}
+
+func (p *marshalPoint) Encode(w Writer) error {
+	w.WriteInt16(p.X)
+	return w.WriteInt16(p.Y)
+}
+
+func (p *marshalPoint) Decode(r Reader) error {
+	p.X = r.ReadInt16()
+	p.Y = r.ReadInt16()
+	return nil
+}
+
+func TestMarshal_Methods(t *testing.T) {
+	src := struct {
+		Points []marshalPoint
+	}{
+		Points: []marshalPoint{{X: 1, Y: 2}, {X: -3, Y: 4}},
+	}
+
+	w := NewBufferWriter(64)
+
+	if err := Marshal(w, &src); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := src
+	dst.Points = nil
+
+	if err := Unmarshal(NewBufferReader(w.Bytes()), &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(src, dst) {
+		t.Errorf("expected %+v, got %+v", src, dst)
+	}
+}
+
+func TestReadVal(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStreamWriter(&buf)
+
+	for _, v := range []any{&marshalPoint{X: 1, Y: 2}, int32(-5), 1.5, true} {
+		if err := w.WriteVal(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		p marshalPoint
+		i int32
+		f float64
+		b bool
+	)
+
+	r := NewStreamReader(&buf)
+
+	for _, v := range []any{&p, &i, &f, &b} {
+		if err := r.ReadVal(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if p.X != 1 || p.Y != 2 || i != -5 || f != 1.5 || !b {
+		t.Errorf("unexpected values: %v %d %f %v", p, i, f, b)
+	}
+
+	if err := r.ReadVal(new(string)); err != ErrUnknownValue {
+		t.Errorf("expected ErrUnknownValue, got %v", err)
+	}
+}
@@ -0,0 +1,376 @@
+package binary
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/webmafia/fast"
+)
+
+// WireType is the wire type of a tagged field, compatible with the protobuf encoding.
+type WireType uint8
+
+const (
+	WireVarint     WireType = 0
+	WireFixed64    WireType = 1
+	WireBytes      WireType = 2
+	WireStartGroup WireType = 3 // Deprecated groups, only supported by SkipField.
+	WireEndGroup   WireType = 4 // Deprecated groups, only supported by SkipField.
+	WireFixed32    WireType = 5
+)
+
+// MaxFieldNumber is the largest allowed field number.
+const MaxFieldNumber = 1<<29 - 1
+
+// MaxGroupDepth is the maximum nesting of groups that SkipField skips.
+const MaxGroupDepth = 100
+
+var messagePool = fast.NewPool[BufferWriter](nil, func(b *BufferWriter) {
+	b.Reset()
+})
+
+// WriteTag writes a field tag, consisting of the field number and wire type.
+func WriteTag(w Writer, field uint32, typ WireType) error {
+	return w.WriteUvarint(uint64(field)<<3 | uint64(typ))
+}
+
+// WriteFieldVarint writes a varint field (protobuf uint32, uint64 and enum).
+func WriteFieldVarint(w Writer, field uint32, v uint64) (err error) {
+	if err = WriteTag(w, field, WireVarint); err == nil {
+		err = w.WriteUvarint(v)
+	}
+
+	return
+}
+
+// WriteFieldInt64 writes a two's complement varint field (protobuf int32 and int64).
+func WriteFieldInt64(w Writer, field uint32, v int64) error {
+	return WriteFieldVarint(w, field, uint64(v))
+}
+
+// WriteFieldSint64 writes a zigzag-encoded varint field (protobuf sint32 and sint64).
+func WriteFieldSint64(w Writer, field uint32, v int64) (err error) {
+	if err = WriteTag(w, field, WireVarint); err == nil {
+		err = w.WriteVarint(v)
+	}
+
+	return
+}
+
+// WriteFieldBool writes a boolean varint field.
+func WriteFieldBool(w Writer, field uint32, v bool) error {
+	var u uint64
+
+	if v {
+		u = 1
+	}
+
+	return WriteFieldVarint(w, field, u)
+}
+
+// WriteFieldFixed32 writes a little-endian 32-bit field (protobuf fixed32 and sfixed32),
+// regardless of the byte order of w.
+func WriteFieldFixed32(w Writer, field uint32, v uint32) (err error) {
+	if err = WriteTag(w, field, WireFixed32); err != nil {
+		return
+	}
+
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	_, err = w.Write(buf[:])
+	return
+}
+
+// WriteFieldFixed64 writes a little-endian 64-bit field (protobuf fixed64 and sfixed64),
+// regardless of the byte order of w.
+func WriteFieldFixed64(w Writer, field uint32, v uint64) (err error) {
+	if err = WriteTag(w, field, WireFixed64); err != nil {
+		return
+	}
+
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	_, err = w.Write(buf[:])
+	return
+}
+
+// WriteFieldFloat writes a 32-bit float field.
+func WriteFieldFloat(w Writer, field uint32, v float32) error {
+	return WriteFieldFixed32(w, field, math.Float32bits(v))
+}
+
+// WriteFieldDouble writes a 64-bit float field.
+func WriteFieldDouble(w Writer, field uint32, v float64) error {
+	return WriteFieldFixed64(w, field, math.Float64bits(v))
+}
+
+// WriteFieldBytes writes a length-delimited bytes field.
+func WriteFieldBytes(w Writer, field uint32, v []byte) (err error) {
+	if err = WriteTag(w, field, WireBytes); err != nil {
+		return
+	}
+
+	if err = w.WriteUvarint(uint64(len(v))); err != nil {
+		return
+	}
+
+	_, err = w.Write(v)
+	return
+}
+
+// WriteFieldString writes a length-delimited string field.
+func WriteFieldString(w Writer, field uint32, v string) error {
+	return WriteFieldBytes(w, field, fast.StringToBytes(v))
+}
+
+// WriteFieldMessage writes a length-delimited embedded message field.
+func WriteFieldMessage(w Writer, field uint32, v Encoder) (err error) {
+	buf := messagePool.Acquire()
+	defer messagePool.Release(buf)
+
+	if err = v.Encode(buf); err != nil {
+		return
+	}
+
+	return WriteFieldBytes(w, field, buf.Bytes())
+}
+
+// ReadTag reads a field tag. At the end of the stream, io.EOF is returned.
+func ReadTag(r Reader) (field uint32, typ WireType, err error) {
+	v, err := binary.ReadUvarint(r)
+
+	if err != nil {
+		return
+	}
+
+	field, typ = uint32(v>>3), WireType(v&7)
+
+	if field == 0 || v>>3 > MaxFieldNumber {
+		err = fmt.Errorf("%w: field number %d", ErrInvalidValue, v>>3)
+	}
+
+	return
+}
+
+// ReadFieldVarint reads the value of a varint field (protobuf uint32, uint64 and enum).
+func ReadFieldVarint(r Reader) (uint64, error) {
+	return readUvarint(r)
+}
+
+// ReadFieldInt64 reads the value of a two's complement varint field (protobuf int32 and int64).
+func ReadFieldInt64(r Reader) (int64, error) {
+	v, err := readUvarint(r)
+	return int64(v), err
+}
+
+// ReadFieldSint64 reads the value of a zigzag-encoded varint field (protobuf sint32 and sint64).
+func ReadFieldSint64(r Reader) (int64, error) {
+	return readVarint(r)
+}
+
+// ReadFieldBool reads the value of a boolean varint field.
+func ReadFieldBool(r Reader) (bool, error) {
+	v, err := readUvarint(r)
+	return v != 0, err
+}
+
+// ReadFieldFixed32 reads the value of a little-endian 32-bit field (protobuf fixed32 and sfixed32).
+func ReadFieldFixed32(r Reader) (v uint32, err error) {
+	var buf [4]byte
+
+	if _, err = io.ReadFull(r, buf[:]); err == nil {
+		v = binary.LittleEndian.Uint32(buf[:])
+	} else if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return
+}
+
+// ReadFieldFixed64 reads the value of a little-endian 64-bit field (protobuf fixed64 and sfixed64).
+func ReadFieldFixed64(r Reader) (v uint64, err error) {
+	var buf [8]byte
+
+	if _, err = io.ReadFull(r, buf[:]); err == nil {
+		v = binary.LittleEndian.Uint64(buf[:])
+	} else if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return
+}
+
+// ReadFieldFloat reads the value of a 32-bit float field.
+func ReadFieldFloat(r Reader) (float32, error) {
+	v, err := ReadFieldFixed32(r)
+	return math.Float32frombits(v), err
+}
+
+// ReadFieldDouble reads the value of a 64-bit float field.
+func ReadFieldDouble(r Reader) (float64, error) {
+	v, err := ReadFieldFixed64(r)
+	return math.Float64frombits(v), err
+}
+
+// ReadFieldBytes reads the value of a length-delimited field. The returned slice may
+// point into the reader's buffer, depending on the Reader implementation.
+func ReadFieldBytes(r Reader) (b []byte, err error) {
+	n, err := readFieldLen(r)
+
+	if err != nil {
+		return
+	}
+
+	if b = r.ReadBytes(n); len(b) < n {
+		if err = r.Error(); err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+	}
+
+	return
+}
+
+// readFieldLen reads the length of a length-delimited field.
+func readFieldLen(r Reader) (n int, err error) {
+	l, err := readUvarint(r)
+
+	if err != nil {
+		return
+	}
+
+	if l > uint64(maxInt) {
+		return 0, ErrTooLarge
+	}
+
+	return int(l), nil
+}
+
+// ReadFieldString reads the value of a length-delimited string field. The returned string
+// may point into the reader's buffer, depending on the Reader implementation.
+func ReadFieldString(r Reader) (string, error) {
+	b, err := ReadFieldBytes(r)
+	return fast.BytesToString(b), err
+}
+
+// ReadFieldMessage reads the value of an embedded message field, and decodes it into v
+// through a Reader bounded to the message.
+func ReadFieldMessage(r Reader, v Decoder) (err error) {
+	b, err := ReadFieldBytes(r)
+
+	if err != nil {
+		return
+	}
+
+	msg := NewBufferReader(b)
+
+	if err = v.Decode(msg); err != nil {
+		return
+	}
+
+	return msg.Error()
+}
+
+// SkipField skips the value of a field with number field and wire type typ, e.g. an unknown
+// field. Groups are skipped up to and including their matching end-group tag, and may be
+// nested up to MaxGroupDepth levels.
+func SkipField(r Reader, field uint32, typ WireType) (err error) {
+	if typ != WireStartGroup {
+		return skipValue(r, typ)
+	}
+
+	// Groups are skipped iteratively, so that deeply nested groups can't overflow the stack.
+	groups := []uint32{field}
+
+	for len(groups) > 0 {
+		if field, typ, err = ReadTag(r); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return
+		}
+
+		switch typ {
+
+		case WireStartGroup:
+			if len(groups) >= MaxGroupDepth {
+				return ErrTooDeep
+			}
+
+			groups = append(groups, field)
+
+		case WireEndGroup:
+			if open := groups[len(groups)-1]; field != open {
+				return fmt.Errorf("%w: end of group %d inside group %d", ErrInvalidValue, field, open)
+			}
+
+			groups = groups[:len(groups)-1]
+
+		default:
+			if err = skipValue(r, typ); err != nil {
+				return
+			}
+
+		}
+	}
+
+	return
+}
+
+func skipValue(r Reader, typ WireType) (err error) {
+	switch typ {
+
+	case WireVarint:
+		_, err = readUvarint(r)
+
+	case WireFixed64:
+		_, err = ReadFieldFixed64(r)
+
+	case WireFixed32:
+		_, err = ReadFieldFixed32(r)
+
+	case WireBytes:
+		err = skipBytes(r)
+
+	default:
+		err = fmt.Errorf("%w: wire type %d", ErrInvalidValue, typ)
+
+	}
+
+	return
+}
+
+// skipBytes discards the value of a length-delimited field, without allocating it.
+func skipBytes(r Reader) (err error) {
+	n, err := readFieldLen(r)
+
+	if err != nil {
+		return
+	}
+
+	if _, err = io.CopyN(io.Discard, r, int64(n)); err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return
+}
+
+// readUvarint reads an uvarint value, where the end of the stream is unexpected.
+func readUvarint(r Reader) (v uint64, err error) {
+	if v, err = binary.ReadUvarint(r); err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return
+}
+
+// readVarint reads a varint value, where the end of the stream is unexpected.
+func readVarint(r Reader) (v int64, err error) {
+	if v, err = binary.ReadVarint(r); err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return
+}
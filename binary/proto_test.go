@@ -0,0 +1,164 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+type protoV1 struct {
+	ID   uint64
+	Name string
+}
+
+func (m *protoV1) Encode(w Writer) error {
+	WriteFieldVarint(w, 1, m.ID)
+	return WriteFieldString(w, 2, m.Name)
+}
+
+type protoV2 struct {
+	protoV1
+	Score float64
+	Delta int64
+	Child *protoV1
+}
+
+func (m *protoV2) Encode(w Writer) error {
+	m.protoV1.Encode(w)
+	WriteFieldDouble(w, 3, m.Score)
+	WriteFieldSint64(w, 4, m.Delta)
+	return WriteFieldMessage(w, 5, m.Child)
+}
+
+func (m *protoV1) Decode(r Reader) (err error) {
+	for {
+		field, typ, err := ReadTag(r)
+
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		switch field {
+		case 1:
+			m.ID, err = ReadFieldVarint(r)
+		case 2:
+			m.Name, err = ReadFieldString(r)
+		default:
+			err = SkipField(r, field, typ)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func TestProto_WireCompatible(t *testing.T) {
+	w := NewBufferWriter(64)
+	(&protoV1{ID: 150, Name: "testing"}).Encode(w)
+
+	// Examples from https://protobuf.dev/programming-guides/encoding/
+	exp := []byte{0x08, 0x96, 0x01, 0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g'}
+
+	if !bytes.Equal(w.Bytes(), exp) {
+		t.Errorf("expected %x, got %x", exp, w.Bytes())
+	}
+}
+
+func TestProto_SkipUnknown(t *testing.T) {
+	w := NewBufferWriter(64)
+	src := protoV2{
+		protoV1: protoV1{ID: 123, Name: "foo"},
+		Score:   1.5,
+		Delta:   -7,
+		Child:   &protoV1{ID: 456, Name: "bar"},
+	}
+
+	if err := src.Encode(w); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst protoV1
+
+	if err := dst.Decode(NewBufferReader(w.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst != src.protoV1 {
+		t.Errorf("expected %v, got %v", src.protoV1, dst)
+	}
+
+	if err := dst.Decode(NewBufferReader(w.Bytes()[:w.Len()-2])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestProto_SkipGroup(t *testing.T) {
+	group := func(tags ...[2]uint32) []byte {
+		w := NewBufferWriter(64)
+
+		for _, tag := range tags {
+			WriteTag(w, tag[0], WireType(tag[1]))
+		}
+
+		return w.Bytes()
+	}
+
+	start, end := uint32(WireStartGroup), uint32(WireEndGroup)
+
+	// Field 1 is the group being skipped, whose start tag has already been read.
+	if err := SkipField(NewBufferReader(group([2]uint32{2, start}, [2]uint32{2, end}, [2]uint32{1, end})), 1, WireStartGroup); err != nil {
+		t.Errorf("expected a nested group to be skipped, got %v", err)
+	}
+
+	if err := SkipField(NewBufferReader(group([2]uint32{2, end})), 1, WireStartGroup); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("expected a mismatched end group to fail with ErrInvalidValue, got %v", err)
+	}
+
+	deep := make([][2]uint32, MaxGroupDepth)
+
+	for i := range deep {
+		deep[i] = [2]uint32{1, start}
+	}
+
+	if err := SkipField(NewBufferReader(group(deep...)), 1, WireStartGroup); !errors.Is(err, ErrTooDeep) {
+		t.Errorf("expected ErrTooDeep, got %v", err)
+	}
+}
+
+func TestProto_SkipBytes(t *testing.T) {
+	w := NewBufferWriter(1 << 20)
+	w.WriteUvarint(1 << 20)
+	w.Write(make([]byte, 1<<20))
+	r := NewBufferReader(w.Bytes())
+
+	// The skipped value is discarded rather than allocated.
+	allocs := testing.AllocsPerRun(10, func() {
+		r.Reset()
+
+		if err := SkipField(r, 1, WireBytes); err != nil || r.Len() != 0 {
+			t.Fatalf("expected the value to be skipped, got %d bytes left (%v)", r.Len(), err)
+		}
+	})
+
+	if allocs > 1 {
+		t.Errorf("expected at most 1 allocation, got %v", allocs)
+	}
+
+	if err := SkipField(NewBufferReader(w.Bytes()[:1000]), 1, WireBytes); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestProto_ReadFieldBytes_Hostile(t *testing.T) {
+	w := NewBufferWriter(16)
+	w.WriteUvarint(uint64(maxInt))
+	w.WriteString("foo")
+
+	if _, err := ReadFieldBytes(NewStreamReader(bytes.NewReader(w.Bytes()))); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package binary
+
+import (
+	"io"
+	"time"
+
+	"github.com/webmafia/fast"
+	"github.com/webmafia/fast/ringbuf"
+)
+
+var _ Reader = (*RingReader)(nil)
+
+// A RingReader reads binary data from a ringbuf.Reader or ringbuf.LimitedReader. Byte
+// slices and strings are returned without copying, and are only valid until the next
+// read. Reads past the end (or limit) return zero values and record a sticky error,
+// available through Error.
+//
+// With manual flush enabled, a partially decoded message can be retried once more data
+// has arrived by calling Rewind, and released by calling Flush once fully decoded.
+type RingReader struct {
+	r     ringbuf.RingBufferReader
+	err   error
+	order ByteOrder
+}
+
+// NewRingReader creates a RingReader reading from r, and accepts an optional byte
+// order (defaults to LittleEndian).
+func NewRingReader(r ringbuf.RingBufferReader, order ...ByteOrder) *RingReader {
+	return &RingReader{
+		r:     r,
+		order: byteOrder(order),
+	}
+}
+
+// Error returns the first error that occurred while reading, if any.
+func (b *RingReader) Error() error {
+	return b.err
+}
+
+// Len returns the number of unread bytes currently buffered.
+func (b *RingReader) Len() int {
+	return b.r.Buffered()
+}
+
+// Cap returns the size of the ring buffer.
+func (b *RingReader) Cap() int {
+	return ringbuf.BufferSize
+}
+
+// Reset resets the RingReader to read from r, and clears any error.
+func (b *RingReader) Reset(r ringbuf.RingBufferReader) {
+	b.r = r
+	b.err = nil
+}
+
+// SetManualFlush sets whether read data must be released manually by calling Flush.
+func (b *RingReader) SetManualFlush(v bool) {
+	b.r.RingReader().SetManualFlush(v)
+}
+
+// Flush releases all read data, so that it can be overwritten.
+func (b *RingReader) Flush() {
+	b.r.RingReader().Flush()
+}
+
+// Rewind rewinds back to the last flush, and clears any error.
+func (b *RingReader) Rewind() {
+	switch r := b.r.(type) {
+	case *ringbuf.LimitedReader:
+		r.Rewind()
+	default:
+		r.RingReader().Rewind()
+	}
+
+	b.err = nil
+}
+
+// fail records the first error as a DecodeError at the current offset since the last
+// reset of the underlying reader.
+func (b *RingReader) fail(err error) {
+	if b.err == nil {
+		rr := b.r.RingReader()
+		b.err = &DecodeError{Offset: int64(rr.TotalRead() - rr.Buffered()), Err: err}
+	}
+}
+
+// next returns the next n bytes without copying and advances the read pointer. If
+// fewer than n bytes are available, the error is recorded and nil is returned.
+func (b *RingReader) next(n int) []byte {
+	if b.err != nil {
+		return nil
+	}
+
+	if n < 0 {
+		b.fail(ErrNegativeCount)
+		return nil
+	}
+
+	p, err := b.r.ReadBytes(n)
+
+	if len(p) < n {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		b.fail(err)
+		return nil
+	}
+
+	return p
+}
+
+func (b *RingReader) Read(dst []byte) (int, error) {
+	return b.r.Read(dst)
+}
+
+func (b *RingReader) ReadByte() (byte, error) {
+	return b.r.ReadByte()
+}
+
+// ReadBytes returns the next n bytes. Up to ringbuf.BufferSize bytes are returned
+// without copying, and are only valid until the next read.
+func (b *RingReader) ReadBytes(n int) []byte {
+	if n <= ringbuf.BufferSize {
+		return b.next(n)
+	}
+
+	if b.err != nil {
+		return nil
+	}
+
+	if lr, ok := b.r.(*ringbuf.LimitedReader); ok && n > lr.Remaining() {
+		b.fail(io.ErrUnexpectedEOF)
+		return nil
+	}
+
+	dst, err := readFull(b.r, n)
+
+	if err != nil {
+		b.fail(io.ErrUnexpectedEOF)
+		return nil
+	}
+
+	return dst
+}
+
+// readRaw fills p with the next len(p) bytes, and reports whether it succeeded.
+func (b *RingReader) readRaw(p []byte) bool {
+	if b.err != nil {
+		return false
+	}
+
+	if _, err := io.ReadFull(b.r, p); err != nil {
+		b.fail(io.ErrUnexpectedEOF)
+		return false
+	}
+
+	return true
+}
+
+// ReadString returns the next n bytes as a string. Up to ringbuf.BufferSize bytes
+// are returned without copying, and are only valid until the next read.
+func (b *RingReader) ReadString(n int) string {
+	return fast.BytesToString(b.ReadBytes(n))
+}
+
+// Read a type that implements Decoder
+func (b *RingReader) ReadDec(v Decoder) error {
+	return v.Decode(b)
+}
+
+// ReadVal reads a value into ptr, which must be a pointer to a fixed-size scalar, a
+// time.Time or a time.Duration, or implement Decoder. Strings and byte slices are written
+// by WriteVal without a length, and can therefore not be read back by ReadVal.
+func (b *RingReader) ReadVal(ptr any) error {
+	switch v := ptr.(type) {
+
+	case Decoder:
+		return b.ReadDec(v)
+
+	case *int:
+		*v = b.ReadInt()
+
+	case *int8:
+		*v = b.ReadInt8()
+
+	case *int16:
+		*v = b.ReadInt16()
+
+	case *int32:
+		*v = b.ReadInt32()
+
+	case *int64:
+		*v = b.ReadInt64()
+
+	case *uint:
+		*v = b.ReadUint()
+
+	case *uint8:
+		*v = b.ReadUint8()
+
+	case *uint16:
+		*v = b.ReadUint16()
+
+	case *uint32:
+		*v = b.ReadUint32()
+
+	case *uint64:
+		*v = b.ReadUint64()
+
+	case *float32:
+		*v = b.ReadFloat32()
+
+	case *float64:
+		*v = b.ReadFloat64()
+
+	case *bool:
+		*v = b.ReadBool()
+
+	case *time.Time:
+		*v = b.ReadTime()
+
+	case *time.Duration:
+		*v = b.ReadDuration()
+
+	default:
+		return ErrUnknownValue
+
+	}
+
+	return b.err
+}
@@ -0,0 +1,5 @@
+package binary
+
+func (b *RingReader) ReadBool() bool {
+	return b.ReadUint8() != 0
+}
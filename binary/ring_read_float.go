@@ -0,0 +1,15 @@
+package binary
+
+import (
+	"math"
+)
+
+// Read float32
+func (b *RingReader) ReadFloat32() (v float32) {
+	return math.Float32frombits(b.ReadUint32())
+}
+
+// Read float64
+func (b *RingReader) ReadFloat64() (v float64) {
+	return math.Float64frombits(b.ReadUint64())
+}
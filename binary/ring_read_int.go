@@ -0,0 +1,45 @@
+package binary
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+func (b *RingReader) ReadInt8() int8 {
+	return int8(b.ReadUint8())
+}
+
+func (b *RingReader) ReadInt16() int16 {
+	return int16(b.ReadUint16())
+}
+
+func (b *RingReader) ReadInt32() int32 {
+	return int32(b.ReadUint32())
+}
+
+func (b *RingReader) ReadInt64() int64 {
+	return int64(b.ReadUint64())
+}
+
+func (b *RingReader) ReadInt() int {
+	return int(b.ReadInt64())
+}
+
+func (b *RingReader) ReadVarint() (v int64) {
+	if b.err != nil {
+		return
+	}
+
+	v, err := binary.ReadVarint(b.r)
+
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		b.fail(err)
+		return 0
+	}
+
+	return
+}
@@ -0,0 +1,86 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/webmafia/fast/ringbuf"
+)
+
+func TestRingReader_Retry(t *testing.T) {
+	w := NewBufferWriter(64)
+	w.WriteUvarint(6)
+	w.WriteString("foobar")
+	w.WriteUint64(123)
+	msg := w.Bytes()
+
+	var src bytes.Buffer
+	src.Write(msg[:5])
+
+	r := NewRingReader(ringbuf.NewReader(&src))
+	r.SetManualFlush(true)
+
+	decode := func() (s string, v uint64) {
+		s = r.ReadString(int(r.ReadUvarint()))
+		v = r.ReadUint64()
+		return
+	}
+
+	if _, _ = decode(); !errors.Is(r.Error(), io.ErrUnexpectedEOF) {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got %v", r.Error())
+	}
+
+	src.Write(msg[5:])
+	r.Rewind()
+
+	s, v := decode()
+
+	if err := r.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s != "foobar" || v != 123 {
+		t.Errorf("expected foobar and 123, got %s and %d", s, v)
+	}
+
+	r.Flush()
+}
+
+func TestRingReader_Limited(t *testing.T) {
+	w := NewBufferWriter(64)
+	w.WriteUint32(1)
+	w.WriteUint32(2)
+
+	r := NewRingReader(ringbuf.NewReader(bytes.NewReader(w.Bytes())).LimitReader(6))
+
+	if v := r.ReadUint32(); v != 1 {
+		t.Errorf("expected 1, got %d", v)
+	}
+
+	if v := r.ReadUint32(); v != 0 || !errors.Is(r.Error(), io.ErrUnexpectedEOF) {
+		t.Errorf("expected 0 and io.ErrUnexpectedEOF, got %d and %v", v, r.Error())
+	}
+}
+
+func TestRingReader_ReadBytes_Hostile(t *testing.T) {
+	data := bytes.Repeat([]byte{'x'}, 3*ringbuf.BufferSize)
+
+	readers := map[string]*RingReader{
+		"plain":   NewRingReader(ringbuf.NewReader(bytes.NewReader(data))),
+		"limited": NewRingReader(ringbuf.NewReader(bytes.NewReader(data)).LimitReader(2 * ringbuf.BufferSize)),
+	}
+
+	for name, r := range readers {
+		if b := r.ReadBytes(maxInt); b != nil || !errors.Is(r.Error(), io.ErrUnexpectedEOF) {
+			t.Errorf("%s: expected nil and io.ErrUnexpectedEOF, got %d bytes and %v", name, len(b), r.Error())
+		}
+	}
+
+	r := NewRingReader(ringbuf.NewReader(bytes.NewReader(data)))
+
+	if b := r.ReadBytes(len(data)); !bytes.Equal(b, data) {
+		t.Errorf("expected %d bytes, got %d", len(data), len(b))
+	}
+}
@@ -0,0 +1,73 @@
+package binary
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+func (b *RingReader) ReadUint8() (v uint8) {
+	if p := b.next(1); p != nil {
+		v = p[0]
+	}
+
+	return
+}
+
+func (b *RingReader) ReadUint16() (v uint16) {
+	if p := b.next(2); p != nil {
+		if b.order == BigEndian {
+			v = binary.BigEndian.Uint16(p)
+		} else {
+			v = binary.LittleEndian.Uint16(p)
+		}
+	}
+
+	return
+}
+
+func (b *RingReader) ReadUint32() (v uint32) {
+	if p := b.next(4); p != nil {
+		if b.order == BigEndian {
+			v = binary.BigEndian.Uint32(p)
+		} else {
+			v = binary.LittleEndian.Uint32(p)
+		}
+	}
+
+	return
+}
+
+func (b *RingReader) ReadUint64() (v uint64) {
+	if p := b.next(8); p != nil {
+		if b.order == BigEndian {
+			v = binary.BigEndian.Uint64(p)
+		} else {
+			v = binary.LittleEndian.Uint64(p)
+		}
+	}
+
+	return
+}
+
+func (b *RingReader) ReadUint() uint {
+	return uint(b.ReadUint64())
+}
+
+func (b *RingReader) ReadUvarint() (v uint64) {
+	if b.err != nil {
+		return
+	}
+
+	v, err := binary.ReadUvarint(b.r)
+
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		b.fail(err)
+		return 0
+	}
+
+	return
+}
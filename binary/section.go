@@ -0,0 +1,89 @@
+package binary
+
+import "fmt"
+
+// A Section is a length-prefixed region of a BufferWriter, started with BeginSection or
+// BeginFixedSection and finished with EndSection.
+type Section struct {
+	offset int
+	width  int // 0 for an uvarint length
+}
+
+// Reserve appends n zero bytes and returns their offset, so that they can be filled in
+// later through Bytes. If n is negative, Reserve returns ErrNegativeCount.
+func (b *BufferWriter) Reserve(n int) (offset int, err error) {
+	if n < 0 {
+		return 0, ErrNegativeCount
+	}
+
+	offset = len(b.buf)
+	b.buf = append(b.buf, make([]byte, n)...)
+	return
+}
+
+// BeginSection starts a section prefixed with its uvarint length, as written by Marshal for
+// strings and slices. One byte is reserved for the length, and the section is shifted in
+// EndSection if it turns out to need more.
+func (b *BufferWriter) BeginSection() Section {
+	offset, _ := b.Reserve(1)
+	return Section{offset: offset}
+}
+
+// BeginFixedSection starts a section prefixed with its length as a 1, 2, 4 or 8 byte
+// integer in the writer's byte order. The section is never shifted.
+func (b *BufferWriter) BeginFixedSection(width int) (s Section, err error) {
+	switch width {
+	case 1, 2, 4, 8:
+	default:
+		return s, fmt.Errorf("%w: length width %d", ErrInvalidValue, width)
+	}
+
+	s.offset, _ = b.Reserve(width)
+	s.width = width
+	return
+}
+
+// EndSection writes the length of everything written since s was started. Nested sections
+// must be ended in reverse order. A length that doesn't fit in a fixed width is rejected
+// with ErrTooLarge.
+func (b *BufferWriter) EndSection(s Section) (err error) {
+	end := len(b.buf)
+
+	if s.width == 0 {
+		n := end - s.offset - 1
+
+		if size := SizeLen(n); size > 1 {
+			b.buf = append(b.buf, make([]byte, size-1)...)
+			copy(b.buf[s.offset+size:], b.buf[s.offset+1:end])
+			end += size - 1
+		}
+
+		b.buf = b.buf[:s.offset]
+		err = b.WriteUvarint(uint64(n))
+		b.buf = b.buf[:end]
+		return
+	}
+
+	n := uint64(end - s.offset - s.width)
+
+	if s.width < 8 && n >= 1<<(8*s.width) {
+		return fmt.Errorf("%w: %d bytes exceeds a %d byte length", ErrTooLarge, n, s.width)
+	}
+
+	// Appending at the section's offset overwrites the reserved bytes in place.
+	b.buf = b.buf[:s.offset]
+
+	switch s.width {
+	case 1:
+		err = b.WriteUint8(uint8(n))
+	case 2:
+		err = b.WriteUint16(uint16(n))
+	case 4:
+		err = b.WriteUint32(uint32(n))
+	case 8:
+		err = b.WriteUint64(n)
+	}
+
+	b.buf = b.buf[:end]
+	return
+}
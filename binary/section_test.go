@@ -0,0 +1,88 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSection(t *testing.T) {
+	inner := strings.Repeat("x", 200)
+
+	w := NewBufferWriter(8)
+	outer := w.BeginSection()
+	w.WriteUint8(1)
+	s := w.BeginSection()
+	w.WriteString(inner)
+
+	if err := w.EndSection(s); err != nil {
+		t.Fatal(err)
+	}
+
+	w.WriteUint8(2)
+
+	if err := w.EndSection(outer); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same message encoded through temporary buffers.
+	tmp := NewBufferWriter(0)
+	tmp.WriteUint8(1)
+	tmp.WriteUvarint(uint64(len(inner)))
+	tmp.WriteString(inner)
+	tmp.WriteUint8(2)
+
+	expected := NewBufferWriter(0)
+	expected.WriteUvarint(uint64(tmp.Len()))
+	expected.Write(tmp.Bytes())
+
+	if !bytes.Equal(w.Bytes(), expected.Bytes()) {
+		t.Errorf("expected %x, got %x", expected.Bytes(), w.Bytes())
+	}
+}
+
+func TestSection_Fixed(t *testing.T) {
+	for _, order := range []ByteOrder{LittleEndian, BigEndian} {
+		w := NewBufferWriter(0, order)
+		s, err := w.BeginFixedSection(4)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.WriteString("foobar")
+
+		if err = w.EndSection(s); err != nil {
+			t.Fatal(err)
+		}
+
+		r := NewBufferReader(w.Bytes(), order)
+
+		if n := r.ReadUint32(); n != 6 {
+			t.Errorf("%s: expected a length of 6, got %d", order, n)
+		}
+
+		if str := r.ReadString(6); str != "foobar" {
+			t.Errorf("%s: expected 'foobar', got '%s'", order, str)
+		}
+	}
+}
+
+func TestSection_TooLarge(t *testing.T) {
+	w := NewBufferWriter(0)
+	s, _ := w.BeginFixedSection(1)
+	w.Reserve(256)
+
+	if err := w.EndSection(s); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+
+	if _, err := w.BeginFixedSection(3); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("expected ErrInvalidValue, got %v", err)
+	}
+
+	if _, err := w.Reserve(-1); !errors.Is(err, ErrNegativeCount) {
+		t.Errorf("expected ErrNegativeCount, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package binary
+
+import (
+	"math/bits"
+	"time"
+)
+
+// A Sizer reports the exact number of bytes its Encode method will write. Writers use it
+// to allocate space once before encoding.
+type Sizer interface {
+	EncodedSize() int
+}
+
+func SizeBool() int    { return 1 }
+func SizeUint8() int   { return 1 }
+func SizeUint16() int  { return 2 }
+func SizeUint32() int  { return 4 }
+func SizeUint64() int  { return 8 }
+func SizeUint() int    { return 8 }
+func SizeInt8() int    { return 1 }
+func SizeInt16() int   { return 2 }
+func SizeInt32() int   { return 4 }
+func SizeInt64() int   { return 8 }
+func SizeInt() int     { return 8 }
+func SizeFloat32() int { return 4 }
+func SizeFloat64() int { return 8 }
+
+// SizeUvarint returns the number of bytes WriteUvarint writes for v.
+func SizeUvarint(v uint64) int {
+	return (bits.Len64(v|1) + 6) / 7
+}
+
+// SizeVarint returns the number of bytes WriteVarint writes for v.
+func SizeVarint(v int64) int {
+	return SizeUvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+// SizeLen returns the number of bytes of an uvarint length prefix for n.
+func SizeLen(n int) int {
+	return SizeUvarint(uint64(n))
+}
+
+// SizeString returns the number of bytes of s prefixed with its length, as encoded by
+// Marshal.
+func SizeString(s string) int {
+	return SizeLen(len(s)) + len(s)
+}
+
+// SizeBytes returns the number of bytes of b prefixed with its length, as encoded by
+// Marshal.
+func SizeBytes(b []byte) int {
+	return SizeLen(len(b)) + len(b)
+}
+
+// SizeTime returns the number of bytes WriteTime writes for t.
+func SizeTime(t time.Time) int {
+	_, offset := t.Zone()
+	return SizeVarint(t.Unix()) + SizeUvarint(uint64(t.Nanosecond())) + SizeVarint(int64(offset))
+}
+
+// SizeDuration returns the number of bytes WriteDuration writes for d.
+func SizeDuration(d time.Duration) int {
+	return SizeVarint(int64(d))
+}
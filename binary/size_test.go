@@ -0,0 +1,98 @@
+package binary
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+type sizedPoint struct {
+	Name string
+	X, Y int64
+}
+
+func (p *sizedPoint) Encode(w Writer) (err error) {
+	if err = w.WriteUvarint(uint64(len(p.Name))); err != nil {
+		return
+	}
+
+	if _, err = w.WriteString(p.Name); err != nil {
+		return
+	}
+
+	if err = w.WriteVarint(p.X); err != nil {
+		return
+	}
+
+	return w.WriteInt64(p.Y)
+}
+
+func (p *sizedPoint) EncodedSize() int {
+	return SizeString(p.Name) + SizeVarint(p.X) + SizeInt64()
+}
+
+func TestSize(t *testing.T) {
+	w := NewBufferWriter(64)
+
+	check := func(name string, expected int, write func()) {
+		t.Helper()
+		w.Reset()
+		write()
+
+		if w.Len() != expected {
+			t.Errorf("%s: expected %d bytes, got %d", name, expected, w.Len())
+		}
+	}
+
+	for _, v := range []uint64{0, 1, 127, 128, 1<<14 - 1, 1 << 14, 1<<63 - 1, math.MaxUint64} {
+		check("uvarint", SizeUvarint(v), func() { w.WriteUvarint(v) })
+	}
+
+	for _, v := range []int64{0, -1, 63, -64, 64, -65, math.MaxInt64, math.MinInt64} {
+		check("varint", SizeVarint(v), func() { w.WriteVarint(v) })
+		check("duration", SizeDuration(time.Duration(v)), func() { w.WriteDuration(time.Duration(v)) })
+	}
+
+	for _, v := range []time.Time{{}, time.Now(), time.Now().In(time.FixedZone("", -3600))} {
+		check("time", SizeTime(v), func() { w.WriteTime(v) })
+	}
+
+	check("bool", SizeBool(), func() { w.WriteBool(true) })
+	check("uint16", SizeUint16(), func() { w.WriteUint16(1) })
+	check("uint32", SizeUint32(), func() { w.WriteUint32(1) })
+	check("uint", SizeUint(), func() { w.WriteUint(1) })
+	check("int", SizeInt(), func() { w.WriteInt(1) })
+	check("float32", SizeFloat32(), func() { w.WriteFloat32(1) })
+	check("float64", SizeFloat64(), func() { w.WriteFloat64(1) })
+}
+
+func TestBufferWriter_WriteEnc_Sizer(t *testing.T) {
+	p := &sizedPoint{Name: "origin", X: -300, Y: 1}
+	w := NewBufferWriter(0)
+
+	if err := w.WriteEnc(p); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Len() != p.EncodedSize() {
+		t.Fatalf("expected %d bytes, got %d", p.EncodedSize(), w.Len())
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() {
+		w := NewBufferWriter(0)
+		w.WriteEnc(p)
+	}); allocs != 2 {
+		t.Errorf("expected 2 allocations, got %v", allocs)
+	}
+
+	// Repeated writes must grow the buffer amortized, rather than once per write.
+	if allocs := testing.AllocsPerRun(10, func() {
+		w := NewBufferWriter(0)
+
+		for range 1000 {
+			w.WriteEnc(p)
+		}
+	}); allocs > 20 {
+		t.Errorf("expected amortized growth, got %v allocations", allocs)
+	}
+}
@@ -0,0 +1,156 @@
+package binary
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// WriteSlice writes an uvarint count followed by each element of s, encoded the same way
+// as Marshal does.
+func WriteSlice[T any](w Writer, s []T) (err error) {
+	c, err := codecOf(reflect.TypeFor[T]())
+
+	if err != nil {
+		return
+	}
+
+	if err = w.WriteUvarint(uint64(len(s))); err != nil {
+		return
+	}
+
+	for i := range s {
+		if err = c.enc(w, unsafe.Pointer(&s[i]), 0); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// ReadSlice reads a slice written by WriteSlice. A count larger than maxLen is rejected
+// with ErrTooLarge, and the slice is grown as elements are decoded rather than allocated
+// upfront. An empty slice is returned as nil.
+func ReadSlice[T any](r Reader, maxLen int) (s []T, err error) {
+	c, err := codecOf(reflect.TypeFor[T]())
+
+	if err != nil {
+		return
+	}
+
+	n, err := readCount(r, maxLen)
+
+	if err != nil || n == 0 {
+		return
+	}
+
+	s = make([]T, 0, PreallocLen[T](n))
+
+	for range n {
+		var v T
+
+		if err = c.dec(r, unsafe.Pointer(&v), 0); err == nil {
+			err = r.Error()
+		}
+
+		if err != nil {
+			return s, IndexError(err, len(s))
+		}
+
+		s = append(s, v)
+	}
+
+	return
+}
+
+// WriteMap writes an uvarint count followed by each key and value of m, encoded the same
+// way as Marshal does.
+func WriteMap[K comparable, V any](w Writer, m map[K]V) (err error) {
+	kc, err := codecOf(reflect.TypeFor[K]())
+
+	if err != nil {
+		return
+	}
+
+	vc, err := codecOf(reflect.TypeFor[V]())
+
+	if err != nil {
+		return
+	}
+
+	if err = w.WriteUvarint(uint64(len(m))); err != nil {
+		return
+	}
+
+	for k, v := range m {
+		if err = kc.enc(w, unsafe.Pointer(&k), 0); err != nil {
+			return
+		}
+
+		if err = vc.enc(w, unsafe.Pointer(&v), 0); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// ReadMap reads a map written by WriteMap. A count larger than maxLen is rejected with
+// ErrTooLarge, and the map is grown as entries are decoded rather than allocated upfront.
+// An empty map is returned as nil.
+func ReadMap[K comparable, V any](r Reader, maxLen int) (m map[K]V, err error) {
+	kc, err := codecOf(reflect.TypeFor[K]())
+
+	if err != nil {
+		return
+	}
+
+	vc, err := codecOf(reflect.TypeFor[V]())
+
+	if err != nil {
+		return
+	}
+
+	n, err := readCount(r, maxLen)
+
+	if err != nil || n == 0 {
+		return
+	}
+
+	m = make(map[K]V, preallocLen(n, unsafe.Sizeof(*new(K))+unsafe.Sizeof(*new(V))))
+
+	for range n {
+		var (
+			k K
+			v V
+		)
+
+		if err = kc.dec(r, unsafe.Pointer(&k), 0); err == nil {
+			err = r.Error()
+		}
+
+		if err != nil {
+			return
+		}
+
+		if err = vc.dec(r, unsafe.Pointer(&v), 0); err == nil {
+			err = r.Error()
+		}
+
+		if err != nil {
+			return m, KeyError(err, k)
+		}
+
+		m[k] = v
+	}
+
+	return
+}
+
+func readCount(r Reader, maxLen int) (n int, err error) {
+	if n, err = ReadLen(r); err == nil && n > maxLen {
+		err = fmt.Errorf("%w: %d elements exceeds %d", ErrTooLarge, n, maxLen)
+	}
+
+	return
+}
@@ -0,0 +1,87 @@
+package binary
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSlice(t *testing.T) {
+	src := []marshalItem{{Name: "foo", Price: 1.5}, {Name: "bar", Tags: []string{"a"}}}
+	w := NewBufferWriter(64)
+
+	if err := WriteSlice(w, src); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, err := ReadSlice[marshalItem](NewBufferReader(w.Bytes()), 2)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(src, dst) {
+		t.Errorf("expected %+v, got %+v", src, dst)
+	}
+
+	// Must be compatible with Marshal.
+	var ref []marshalItem
+
+	if err = Unmarshal(NewBufferReader(w.Bytes()), &ref); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(src, ref) {
+		t.Errorf("expected %+v, got %+v", src, ref)
+	}
+
+	if _, err = ReadSlice[marshalItem](NewBufferReader(w.Bytes()), 1); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestMap(t *testing.T) {
+	src := map[string][]int32{"foo": {1, 2}, "bar": {-3}}
+	w := NewBufferWriter(64)
+
+	if err := WriteMap(w, src); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, err := ReadMap[string, []int32](NewBufferReader(w.Bytes()), 2)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(src, dst) {
+		t.Errorf("expected %+v, got %+v", src, dst)
+	}
+
+	if _, err = ReadMap[string, []int32](NewBufferReader(w.Bytes()), 1); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestSlice_MaliciousLength(t *testing.T) {
+	w := NewBufferWriter(16)
+	w.WriteUvarint(1 << 40)
+	w.WriteUint64(1)
+
+	// These would run out of memory if the length was allocated upfront.
+	if _, err := ReadSlice[uint64](NewBufferReader(w.Bytes()), 1<<50); err == nil {
+		t.Error("expected an error")
+	}
+
+	var dst []uint64
+
+	if err := Unmarshal(NewBufferReader(w.Bytes()), &dst); err == nil {
+		t.Error("expected an error")
+	}
+
+	var b []byte
+
+	if err := Unmarshal(NewBufferReader(w.Bytes()), &b); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -3,25 +3,32 @@ package binary
 import (
 	"bufio"
 	"io"
+	"time"
 
 	"github.com/webmafia/fast"
 )
 
 var _ Reader = (*StreamReader)(nil)
 
-// A StreamReader is used to efficiently read binary data from
-// an io.Reader.
+// A StreamReader is used to efficiently read binary data from an io.Reader. Failed
+// reads return zero values and record a sticky DecodeError, available through Error.
 type StreamReader struct {
-	buf *bufio.Reader
-	err error
+	buf    *bufio.Reader
+	err    error
+	offset int64
+	order  ByteOrder
 }
 
-func NewStreamReader(r io.Reader) *StreamReader {
+// NewStreamReader creates a StreamReader reading from r, and accepts an optional
+// byte order (defaults to LittleEndian).
+func NewStreamReader(r io.Reader, order ...ByteOrder) *StreamReader {
 	return &StreamReader{
-		buf: bufio.NewReader(r),
+		buf:   bufio.NewReader(r),
+		order: byteOrder(order),
 	}
 }
 
+// Error returns the first error that occurred while reading, if any.
 func (b *StreamReader) Error() error {
 	return b.err
 }
@@ -38,18 +45,43 @@ func (b *StreamReader) Cap() int {
 	return b.buf.Size()
 }
 
-// Reset resets the StreamReader to be empty.
+// Offset returns the number of bytes read since the StreamReader was created or reset.
+func (b *StreamReader) Offset() int64 {
+	return b.offset
+}
+
+// Reset resets the StreamReader to read from r, and clears any error.
 func (b *StreamReader) Reset(r io.Reader) {
 	b.buf.Reset(r)
+	b.err = nil
+	b.offset = 0
+}
+
+// fail records the first error as a DecodeError at the current offset.
+func (b *StreamReader) fail(err error) {
+	if b.err == nil {
+		b.err = &DecodeError{Offset: b.offset, Err: err}
+	}
 }
 
-// Write appends the contents of p to b's buffer.
-// Write always returns len(p), nil.
+// Read implements io.Reader. After a failed read, the recorded error is returned.
 func (b *StreamReader) Read(dst []byte) (n int, err error) {
-	return b.buf.Read(dst)
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	n, err = b.buf.Read(dst)
+	b.offset += int64(n)
+	return
 }
 
+// ReadFull reads exactly len(dst) bytes. If none could be read, io.EOF is returned, and
+// if only some, io.ErrUnexpectedEOF. After a failed read, the recorded error is returned.
 func (b *StreamReader) ReadFull(dst []byte) (err error) {
+	if b.err != nil {
+		return b.err
+	}
+
 	var n int
 	l := len(dst)
 
@@ -59,6 +91,8 @@ func (b *StreamReader) ReadFull(dst []byte) (err error) {
 		n += nn
 	}
 
+	b.offset += int64(n)
+
 	if n >= l {
 		err = nil
 	} else if n > 0 && err == io.EOF {
@@ -68,18 +102,62 @@ func (b *StreamReader) ReadFull(dst []byte) (err error) {
 	return
 }
 
-// WriteByte appends the byte c to b's buffer.
-// The returned error is always nil.
-func (b *StreamReader) ReadByte() (byte, error) {
-	return b.buf.ReadByte()
+// ReadByte implements io.ByteReader. After a failed read, the recorded error is returned.
+func (b *StreamReader) ReadByte() (c byte, err error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	if c, err = b.buf.ReadByte(); err == nil {
+		b.offset++
+	}
+
+	return
 }
 
-// WriteString appends the contents of s to b's buffer.
-// It returns the length of s and a nil error.
+// readByte reads a byte, and records any error.
+func (b *StreamReader) readByte() byte {
+	c, err := b.ReadByte()
+
+	if err != nil {
+		b.fail(err)
+	}
+
+	return c
+}
+
+// readRaw fills p with the next len(p) bytes, and reports whether it succeeded. Any error
+// is recorded.
+func (b *StreamReader) readRaw(p []byte) bool {
+	if err := b.ReadFull(p); err != nil {
+		b.fail(err)
+		return false
+	}
+
+	return true
+}
+
+// ReadBytes reads exactly n bytes into a newly allocated slice, which is grown as data
+// arrives so that a malicious n can't allocate more memory than the data backing it. If
+// fewer bytes are available, io.ErrUnexpectedEOF is recorded and nil is returned.
 func (b *StreamReader) ReadBytes(n int) []byte {
-	buf := make([]byte, n)
-	n, _ = b.buf.Read(buf)
-	return buf[:n]
+	if b.err != nil {
+		return nil
+	}
+
+	if n < 0 {
+		b.fail(ErrNegativeCount)
+		return nil
+	}
+
+	buf, err := readFull(b, n)
+
+	if err != nil {
+		b.fail(io.ErrUnexpectedEOF)
+		return nil
+	}
+
+	return buf
 }
 
 // WriteString appends the contents of s to b's buffer.
@@ -89,5 +167,78 @@ func (b *StreamReader) ReadString(n int) string {
 }
 
 func (b *StreamReader) WriteTo(w io.Writer) (n int64, err error) {
-	return b.buf.WriteTo(w)
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	n, err = b.buf.WriteTo(w)
+	b.offset += n
+	return
+}
+
+// Read a type that implements Decoder
+func (b *StreamReader) ReadDec(v Decoder) error {
+	return v.Decode(b)
+}
+
+// ReadVal reads a value into ptr, which must be a pointer to a fixed-size scalar, a
+// time.Time or a time.Duration, or implement Decoder. Strings and byte slices are written
+// by WriteVal without a length, and can therefore not be read back by ReadVal.
+func (b *StreamReader) ReadVal(ptr any) error {
+	switch v := ptr.(type) {
+
+	case Decoder:
+		return b.ReadDec(v)
+
+	case *int:
+		*v = b.ReadInt()
+
+	case *int8:
+		*v = b.ReadInt8()
+
+	case *int16:
+		*v = b.ReadInt16()
+
+	case *int32:
+		*v = b.ReadInt32()
+
+	case *int64:
+		*v = b.ReadInt64()
+
+	case *uint:
+		*v = b.ReadUint()
+
+	case *uint8:
+		*v = b.ReadUint8()
+
+	case *uint16:
+		*v = b.ReadUint16()
+
+	case *uint32:
+		*v = b.ReadUint32()
+
+	case *uint64:
+		*v = b.ReadUint64()
+
+	case *float32:
+		*v = b.ReadFloat32()
+
+	case *float64:
+		*v = b.ReadFloat64()
+
+	case *bool:
+		*v = b.ReadBool()
+
+	case *time.Time:
+		*v = b.ReadTime()
+
+	case *time.Duration:
+		*v = b.ReadDuration()
+
+	default:
+		return ErrUnknownValue
+
+	}
+
+	return b.err
 }
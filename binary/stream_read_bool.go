@@ -1,7 +1,5 @@
 package binary
 
 func (b *StreamReader) ReadBool() bool {
-	var v byte
-	v, b.err = b.ReadByte()
-	return v != 0
+	return b.readByte() != 0
 }
@@ -0,0 +1,54 @@
+package binary
+
+import (
+	"github.com/webmafia/fast"
+)
+
+// ReadUint16s fills dst with values. On hosts with a matching byte order, the values are
+// copied as-is.
+func (b *StreamReader) ReadUint16s(dst []uint16) {
+	if b.readRaw(asBytes(dst)) {
+		swap16(dst, b.order)
+	}
+}
+
+// ReadInt16s fills dst with values.
+func (b *StreamReader) ReadInt16s(dst []int16) {
+	b.ReadUint16s(fast.SliceToSlice[int16, uint16](dst, len(dst)))
+}
+
+// ReadUint32s fills dst with values. On hosts with a matching byte order, the values are
+// copied as-is.
+func (b *StreamReader) ReadUint32s(dst []uint32) {
+	if b.readRaw(asBytes(dst)) {
+		swap32(dst, b.order)
+	}
+}
+
+// ReadInt32s fills dst with values.
+func (b *StreamReader) ReadInt32s(dst []int32) {
+	b.ReadUint32s(fast.SliceToSlice[int32, uint32](dst, len(dst)))
+}
+
+// ReadUint64s fills dst with values. On hosts with a matching byte order, the values are
+// copied as-is.
+func (b *StreamReader) ReadUint64s(dst []uint64) {
+	if b.readRaw(asBytes(dst)) {
+		swap64(dst, b.order)
+	}
+}
+
+// ReadInt64s fills dst with values.
+func (b *StreamReader) ReadInt64s(dst []int64) {
+	b.ReadUint64s(fast.SliceToSlice[int64, uint64](dst, len(dst)))
+}
+
+// ReadFloat32s fills dst with values.
+func (b *StreamReader) ReadFloat32s(dst []float32) {
+	b.ReadUint32s(fast.SliceToSlice[float32, uint32](dst, len(dst)))
+}
+
+// ReadFloat64s fills dst with values.
+func (b *StreamReader) ReadFloat64s(dst []float64) {
+	b.ReadUint64s(fast.SliceToSlice[float64, uint64](dst, len(dst)))
+}
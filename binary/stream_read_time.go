@@ -0,0 +1,14 @@
+package binary
+
+import "time"
+
+// ReadTime reads a time written by WriteTime, in UTC or a fixed zone with the written
+// offset.
+func (b *StreamReader) ReadTime() time.Time {
+	return readTime(b)
+}
+
+// ReadDuration reads a duration written by WriteDuration.
+func (b *StreamReader) ReadDuration() time.Duration {
+	return time.Duration(b.ReadVarint())
+}
@@ -4,26 +4,49 @@ import (
 	"encoding/binary"
 )
 
-func (b *StreamReader) ReadUint8() (v uint8) {
-	v, b.err = b.ReadByte()
-	return
+func (b *StreamReader) ReadUint8() uint8 {
+	return b.readByte()
 }
 
 func (b *StreamReader) ReadUint16() uint16 {
 	var v [2]byte
-	b.err = b.ReadFull(v[:])
+
+	if !b.readRaw(v[:]) {
+		return 0
+	}
+
+	if b.order == BigEndian {
+		return binary.BigEndian.Uint16(v[:])
+	}
+
 	return binary.LittleEndian.Uint16(v[:])
 }
 
 func (b *StreamReader) ReadUint32() uint32 {
 	var v [4]byte
-	b.err = b.ReadFull(v[:])
+
+	if !b.readRaw(v[:]) {
+		return 0
+	}
+
+	if b.order == BigEndian {
+		return binary.BigEndian.Uint32(v[:])
+	}
+
 	return binary.LittleEndian.Uint32(v[:])
 }
 
 func (b *StreamReader) ReadUint64() uint64 {
 	var v [8]byte
-	b.err = b.ReadFull(v[:])
+
+	if !b.readRaw(v[:]) {
+		return 0
+	}
+
+	if b.order == BigEndian {
+		return binary.BigEndian.Uint64(v[:])
+	}
+
 	return binary.LittleEndian.Uint64(v[:])
 }
 
@@ -31,7 +54,17 @@ func (b *StreamReader) ReadUint() uint {
 	return uint(b.ReadUint64())
 }
 
-func (b *StreamReader) ReadUvarint() (v uint64) {
-	v, b.err = binary.ReadUvarint(b.buf)
-	return
+func (b *StreamReader) ReadUvarint() uint64 {
+	if b.err != nil {
+		return 0
+	}
+
+	v, err := binary.ReadUvarint(b)
+
+	if err != nil {
+		b.fail(err)
+		return 0
+	}
+
+	return v
 }
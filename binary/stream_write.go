@@ -1,57 +1,197 @@
 package binary
 
 import (
+	"encoding"
+	"encoding/binary"
 	"io"
+	"time"
 
 	"github.com/webmafia/fast"
 )
 
-var _ Writer = (*StreamWriter)(nil)
+var (
+	_ Writer         = (*StreamWriter)(nil)
+	_ io.WriteCloser = (*StreamWriter)(nil)
+)
+
+const defaultStreamBufferSize = 4096
 
-// A Stream is used to efficiently write binary data to
-// an io.Writer.
+// A StreamWriter is used to efficiently write binary data to an io.Writer. Writes
+// are gathered in an internal buffer, which is only written to the underlying
+// io.Writer when full or when Flush or Close is called.
 type StreamWriter struct {
-	w io.Writer
+	w     io.Writer
+	buf   []byte
+	order ByteOrder
+	err   error // Returned by every write once set, e.g. ErrClosed by ChecksumWriter.Close
+}
+
+// NewStreamWriter creates a StreamWriter writing to w with a 4 KiB buffer, and accepts
+// an optional byte order (defaults to LittleEndian).
+func NewStreamWriter(w io.Writer, order ...ByteOrder) *StreamWriter {
+	return NewStreamWriterSize(w, defaultStreamBufferSize, order...)
 }
 
-func NewStreamWriter(w io.Writer) *StreamWriter {
+// NewStreamWriterSize creates a StreamWriter writing to w with a buffer of at least
+// size bytes, and accepts an optional byte order (defaults to LittleEndian).
+func NewStreamWriterSize(w io.Writer, size int, order ...ByteOrder) *StreamWriter {
 	return &StreamWriter{
-		w: w,
+		w:     w,
+		buf:   fast.MakeNoZeroCap(0, max(size, binary.MaxVarintLen64)),
+		order: byteOrder(order),
 	}
 }
 
-// Reset resets the Stream to be empty.
+// NewStreamWriterPool creates a pool of StreamWriters with buffers of size bytes. Acquired
+// writers must be given an io.Writer through Reset, and flushed before being released.
+func NewStreamWriterPool(size int, order ...ByteOrder) *fast.Pool[StreamWriter] {
+	o := byteOrder(order)
+
+	return fast.NewPool(func(b *StreamWriter) {
+		b.buf = fast.MakeNoZeroCap(0, max(size, binary.MaxVarintLen64))
+		b.order = o
+	}, func(b *StreamWriter) {
+		b.Reset(nil)
+	})
+}
+
+// Reset discards any unflushed data, and resets b to write to w.
 func (b *StreamWriter) Reset(w io.Writer) {
 	b.w = w
+	b.buf = b.buf[:0]
+	b.err = nil
 }
 
-// Write appends the contents of p to b's buffer.
-// Write always returns len(p), nil.
-//
-//go:inline
-func (b *StreamWriter) Write(p []byte) (int, error) {
-	return b.w.Write(fast.Noescape(p))
+// Buffered returns the number of bytes that have been written into the buffer.
+func (b *StreamWriter) Buffered() int {
+	return len(b.buf)
+}
+
+// Available returns how many bytes are unused in the buffer.
+func (b *StreamWriter) Available() int {
+	return cap(b.buf) - len(b.buf)
+}
+
+// AvailableBuffer returns an empty buffer with b.Available() capacity. The buffer is
+// intended to be appended to and passed to an immediately succeeding Write call.
+func (b *StreamWriter) AvailableBuffer() []byte {
+	return b.buf[len(b.buf):][:0]
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (b *StreamWriter) Flush() (err error) {
+	if len(b.buf) == 0 {
+		return
+	}
+
+	n, err := b.w.Write(b.buf)
+
+	if n < len(b.buf) && err == nil {
+		err = io.ErrShortWrite
+	}
+
+	if err != nil {
+		// Keep whatever wasn't written, so that the flush can be retried.
+		if n > 0 && n < len(b.buf) {
+			b.buf = b.buf[:copy(b.buf, b.buf[n:])]
+		}
+
+		return
+	}
+
+	b.buf = b.buf[:0]
+	return
+}
+
+// Close implements io.Closer by flushing the buffer. The underlying io.Writer
+// is not closed.
+func (b *StreamWriter) Close() error {
+	return b.Flush()
+}
+
+// reserve flushes the buffer if there isn't room for another n bytes.
+func (b *StreamWriter) reserve(n int) (err error) {
+	if b.err != nil {
+		return b.err
+	}
+
+	if cap(b.buf)-len(b.buf) < n {
+		err = b.Flush()
+	}
+
+	return
+}
+
+// Write appends the contents of p to b's buffer. If p doesn't fit in the buffer
+// even after a flush, it is written directly to the underlying io.Writer.
+func (b *StreamWriter) Write(p []byte) (n int, err error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	if len(p) > b.Available() {
+		if err = b.Flush(); err != nil {
+			return
+		}
+
+		if len(p) > cap(b.buf) {
+			return b.w.Write(fast.Noescape(p))
+		}
+	}
+
+	b.buf = append(b.buf, p...)
+	return len(p), nil
 }
 
 // WriteByte appends the byte c to b's buffer.
-// The returned error is always nil.
 func (b *StreamWriter) WriteByte(c byte) (err error) {
-	_, err = b.Write([]byte{c})
+	if err = b.reserve(1); err == nil {
+		b.buf = append(b.buf, c)
+	}
+
 	return
 }
 
 // WriteString appends the contents of s to b's buffer.
-// It returns the length of s and a nil error.
 func (b *StreamWriter) WriteString(s string) (int, error) {
 	return b.Write(fast.StringToBytes(s))
 }
 
+// WriteVal writes a scalar, time.Time, time.Duration or a value implementing Encoder,
+// fast.BinaryAppender or encoding.BinaryMarshaler. Strings, byte slices and binary
+// representations are written without a length.
 func (b *StreamWriter) WriteVal(val any) error {
 	switch v := val.(type) {
 
 	case Encoder:
 		return b.WriteEnc(v)
 
+	case time.Time:
+		return b.WriteTime(v)
+
+	case time.Duration:
+		return b.WriteDuration(v)
+
+	case fast.BinaryAppender:
+		buf, err := v.AppendBinary(b.AvailableBuffer())
+
+		if err != nil {
+			return err
+		}
+
+		_, err = b.Write(buf)
+		return err
+
+	case encoding.BinaryMarshaler:
+		buf, err := v.MarshalBinary()
+
+		if err != nil {
+			return err
+		}
+
+		_, err = b.Write(buf)
+		return err
+
 	case string:
 		_, err := b.WriteString(v)
 		return err
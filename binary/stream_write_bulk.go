@@ -0,0 +1,81 @@
+package binary
+
+import (
+	"github.com/webmafia/fast"
+)
+
+// WriteUint16s writes all values in v, without any length. On hosts with a matching byte
+// order, the values are copied as-is.
+func (b *StreamWriter) WriteUint16s(v []uint16) (err error) {
+	if b.order.native() {
+		_, err = b.Write(asBytes(v))
+		return
+	}
+
+	for i := range v {
+		if err = b.WriteUint16(v[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WriteInt16s writes all values in v, without any length.
+func (b *StreamWriter) WriteInt16s(v []int16) error {
+	return b.WriteUint16s(fast.SliceToSlice[int16, uint16](v, len(v)))
+}
+
+// WriteUint32s writes all values in v, without any length. On hosts with a matching byte
+// order, the values are copied as-is.
+func (b *StreamWriter) WriteUint32s(v []uint32) (err error) {
+	if b.order.native() {
+		_, err = b.Write(asBytes(v))
+		return
+	}
+
+	for i := range v {
+		if err = b.WriteUint32(v[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WriteInt32s writes all values in v, without any length.
+func (b *StreamWriter) WriteInt32s(v []int32) error {
+	return b.WriteUint32s(fast.SliceToSlice[int32, uint32](v, len(v)))
+}
+
+// WriteUint64s writes all values in v, without any length. On hosts with a matching byte
+// order, the values are copied as-is.
+func (b *StreamWriter) WriteUint64s(v []uint64) (err error) {
+	if b.order.native() {
+		_, err = b.Write(asBytes(v))
+		return
+	}
+
+	for i := range v {
+		if err = b.WriteUint64(v[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WriteInt64s writes all values in v, without any length.
+func (b *StreamWriter) WriteInt64s(v []int64) error {
+	return b.WriteUint64s(fast.SliceToSlice[int64, uint64](v, len(v)))
+}
+
+// WriteFloat32s writes all values in v, without any length.
+func (b *StreamWriter) WriteFloat32s(v []float32) error {
+	return b.WriteUint32s(fast.SliceToSlice[float32, uint32](v, len(v)))
+}
+
+// WriteFloat64s writes all values in v, without any length.
+func (b *StreamWriter) WriteFloat64s(v []float64) error {
+	return b.WriteUint64s(fast.SliceToSlice[float64, uint64](v, len(v)))
+}
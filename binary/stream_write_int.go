@@ -31,8 +31,9 @@ func (b *StreamWriter) WriteInt(v int) error {
 }
 
 func (b *StreamWriter) WriteVarint(v int64) (err error) {
-	var buf [binary.MaxVarintLen64]byte
-	n := binary.PutVarint(buf[:], v)
-	_, err = b.Write(buf[:n])
+	if err = b.reserve(binary.MaxVarintLen64); err == nil {
+		b.buf = binary.AppendVarint(b.buf, v)
+	}
+
 	return
 }
@@ -1,6 +1,7 @@
 package binary
 
 import (
+	"bytes"
 	"io"
 	"testing"
 )
@@ -13,3 +14,48 @@ func BenchmarkStreamWriter(b *testing.B) {
 		w.WriteInt(i)
 	}
 }
+
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestStreamWriter_Buffered(t *testing.T) {
+	var dst countingWriter
+	w := NewStreamWriterSize(&dst, 64)
+
+	for i := range 100 {
+		w.WriteUint32(uint32(i))
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.writes != 7 {
+		t.Errorf("expected 7 writes, got %d", dst.writes)
+	}
+
+	r := NewBufferReader(dst.Bytes())
+
+	for i := range 100 {
+		if v := r.ReadUint32(); v != uint32(i) {
+			t.Fatalf("expected %d, got %d", i, v)
+		}
+	}
+
+	big := make([]byte, 128)
+
+	if _, err := w.Write(big); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Buffered() != 0 || dst.Len() != 528 {
+		t.Errorf("expected a direct write, got %d buffered and %d written", w.Buffered(), dst.Len())
+	}
+}
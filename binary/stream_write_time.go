@@ -0,0 +1,13 @@
+package binary
+
+import "time"
+
+// WriteTime writes t as varint Unix seconds, uvarint nanoseconds and varint zone offset.
+func (b *StreamWriter) WriteTime(t time.Time) error {
+	return writeTime(b, t)
+}
+
+// WriteDuration writes d as a varint of nanoseconds.
+func (b *StreamWriter) WriteDuration(d time.Duration) error {
+	return b.WriteVarint(int64(d))
+}
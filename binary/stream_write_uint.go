@@ -12,36 +12,46 @@ func (b *StreamWriter) WriteUint8(v uint8) error {
 
 // Write uint16
 func (b *StreamWriter) WriteUint16(v uint16) (err error) {
-	_, err = b.Write([]byte{
-		byte(v),
-		byte(v >> 8),
-	})
+	if err = b.reserve(2); err != nil {
+		return
+	}
+
+	if b.order == BigEndian {
+		b.buf = binary.BigEndian.AppendUint16(b.buf, v)
+	} else {
+		b.buf = binary.LittleEndian.AppendUint16(b.buf, v)
+	}
+
 	return
 }
 
 // Write uint32
 func (b *StreamWriter) WriteUint32(v uint32) (err error) {
-	_, err = b.Write([]byte{
-		byte(v),
-		byte(v >> 8),
-		byte(v >> 16),
-		byte(v >> 24),
-	})
+	if err = b.reserve(4); err != nil {
+		return
+	}
+
+	if b.order == BigEndian {
+		b.buf = binary.BigEndian.AppendUint32(b.buf, v)
+	} else {
+		b.buf = binary.LittleEndian.AppendUint32(b.buf, v)
+	}
+
 	return
 }
 
 // Write uint64
 func (b *StreamWriter) WriteUint64(v uint64) (err error) {
-	_, err = b.Write([]byte{
-		byte(v),
-		byte(v >> 8),
-		byte(v >> 16),
-		byte(v >> 24),
-		byte(v >> 32),
-		byte(v >> 40),
-		byte(v >> 48),
-		byte(v >> 56),
-	})
+	if err = b.reserve(8); err != nil {
+		return
+	}
+
+	if b.order == BigEndian {
+		b.buf = binary.BigEndian.AppendUint64(b.buf, v)
+	} else {
+		b.buf = binary.LittleEndian.AppendUint64(b.buf, v)
+	}
+
 	return
 }
 
@@ -51,8 +61,9 @@ func (b *StreamWriter) WriteUint(v uint) error {
 }
 
 func (b *StreamWriter) WriteUvarint(v uint64) (err error) {
-	var buf [binary.MaxVarintLen64]byte
-	n := binary.PutUvarint(buf[:], v)
-	_, err = b.Write(buf[:n])
+	if err = b.reserve(binary.MaxVarintLen64); err == nil {
+		b.buf = binary.AppendUvarint(b.buf, v)
+	}
+
 	return
 }
@@ -0,0 +1,38 @@
+package binary
+
+import "time"
+
+// A time is encoded as varint Unix seconds, uvarint nanoseconds and varint zone offset in
+// seconds. The monotonic clock reading and zone name are not encoded, and times are decoded
+// in UTC or a fixed zone with the same offset.
+func writeTime(w Writer, t time.Time) (err error) {
+	_, offset := t.Zone()
+
+	if err = w.WriteVarint(t.Unix()); err != nil {
+		return
+	}
+
+	if err = w.WriteUvarint(uint64(t.Nanosecond())); err != nil {
+		return
+	}
+
+	return w.WriteVarint(int64(offset))
+}
+
+func readTime(r Reader) time.Time {
+	sec := r.ReadVarint()
+	nsec := r.ReadUvarint()
+	offset := r.ReadVarint()
+
+	if r.Error() != nil {
+		return time.Time{}
+	}
+
+	t := time.Unix(sec, int64(nsec))
+
+	if offset == 0 {
+		return t.UTC()
+	}
+
+	return t.In(time.FixedZone("", int(offset)))
+}
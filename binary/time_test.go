@@ -0,0 +1,115 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+type ipv4 [4]byte
+
+func (ip ipv4) AppendBinary(b []byte) ([]byte, error) {
+	return append(b, ip[:]...), nil
+}
+
+type version struct{ major, minor uint8 }
+
+func (v version) MarshalBinary() ([]byte, error) {
+	if v.major == 0 {
+		return nil, errors.New("no version")
+	}
+
+	return []byte{v.major, v.minor}, nil
+}
+
+func TestWriteVal_Time(t *testing.T) {
+	times := []time.Time{
+		{},
+		time.Date(2024, 2, 29, 13, 37, 0, 123456789, time.UTC),
+		time.Date(1969, 7, 20, 20, 17, 40, 0, time.FixedZone("", -5*3600)),
+		time.Date(2262, 4, 12, 0, 0, 0, 0, time.FixedZone("", 5*3600+1800)),
+	}
+
+	for _, tm := range times {
+		w := NewBufferWriter(64)
+
+		if err := w.WriteVal(tm); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := w.WriteVal(-90 * time.Minute); err != nil {
+			t.Fatal(err)
+		}
+
+		var (
+			gotTime time.Time
+			gotDur  time.Duration
+		)
+
+		r := NewStreamReader(bytes.NewReader(w.Bytes()))
+
+		if err := r.ReadVal(&gotTime); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := r.ReadVal(&gotDur); err != nil {
+			t.Fatal(err)
+		}
+
+		if !gotTime.Equal(tm) {
+			t.Errorf("expected %v, got %v", tm, gotTime)
+		}
+
+		_, expectedOffset := tm.Zone()
+
+		if _, offset := gotTime.Zone(); offset != expectedOffset {
+			t.Errorf("expected offset %d, got %d", expectedOffset, offset)
+		}
+
+		if gotDur != -90*time.Minute {
+			t.Errorf("expected %v, got %v", -90*time.Minute, gotDur)
+		}
+	}
+}
+
+func TestWriteVal_Binary(t *testing.T) {
+	var buf bytes.Buffer
+	sw := NewStreamWriter(&buf)
+	bw := NewBufferWriter(64)
+
+	for _, w := range []Writer{bw, sw} {
+		if err := w.WriteVal(ipv4{127, 0, 0, 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := w.WriteVal(version{1, 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := w.WriteVal(version{}); err == nil {
+			t.Error("expected an error")
+		}
+	}
+
+	sw.Flush()
+	expected := []byte{127, 0, 0, 1, 1, 2}
+
+	if !bytes.Equal(bw.Bytes(), expected) {
+		t.Errorf("expected %v, got %v", expected, bw.Bytes())
+	}
+
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("expected %v, got %v", expected, buf.Bytes())
+	}
+}
+
+func BenchmarkBufferWriter_WriteVal_BinaryAppender(b *testing.B) {
+	w := NewBufferWriter(64)
+	ip := ipv4{127, 0, 0, 1}
+
+	for i := 0; i < b.N; i++ {
+		w.Reset()
+		w.WriteVal(ip)
+	}
+}
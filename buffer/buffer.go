@@ -13,12 +13,19 @@ var (
 	_ io.StringWriter = (*Buffer)(nil)
 	_ io.ReaderFrom   = (*Buffer)(nil)
 	_ io.WriterTo     = (*Buffer)(nil)
+	_ io.Reader       = (*Buffer)(nil)
+	_ io.ByteScanner  = (*Buffer)(nil)
+	_ io.Seeker       = (*Buffer)(nil)
 	_ fmt.Stringer    = (*Buffer)(nil)
 )
 
 // A byte buffer, highly optimized to minimize allocations and GC pressure.
+//
+// Written bytes can also be consumed with Read and its siblings, which advance a read cursor
+// through B. Len, Bytes and String always cover all of B, regardless of the cursor.
 type Buffer struct {
-	B []byte
+	B   []byte
+	off int // Read cursor
 }
 
 func NewBuffer(size int) *Buffer {
@@ -41,6 +48,7 @@ func (b *Buffer) Cap() int {
 
 func (b *Buffer) Reset() {
 	b.B = b.B[:0]
+	b.off = 0
 }
 
 func (b *Buffer) Bytes() []byte {
@@ -105,8 +113,10 @@ func (b *Buffer) ReadFrom(r io.Reader) (int64, error) {
 	}
 }
 
+// WriteTo implements io.WriterTo. It writes all unread bytes, and resets the buffer on
+// success.
 func (b *Buffer) WriteTo(w io.Writer) (int64, error) {
-	n, err := w.Write(b.B)
+	n, err := w.Write(b.B[min(b.off, len(b.B)):])
 
 	if err == nil {
 		b.Reset()
@@ -0,0 +1,101 @@
+package buffer
+
+import (
+	"io"
+	"math"
+)
+
+// Offset returns the read cursor, i.e. the number of bytes consumed from B.
+func (b *Buffer) Offset() int {
+	return min(b.off, len(b.B))
+}
+
+// Read implements io.Reader.
+func (b *Buffer) Read(p []byte) (n int, err error) {
+	if b.off >= len(b.B) {
+		if len(p) == 0 {
+			return
+		}
+
+		return 0, io.EOF
+	}
+
+	n = copy(p, b.B[b.off:])
+	b.off += n
+	return
+}
+
+// ReadByte implements io.ByteReader.
+func (b *Buffer) ReadByte() (byte, error) {
+	if b.off >= len(b.B) {
+		return 0, io.EOF
+	}
+
+	c := b.B[b.off]
+	b.off++
+	return c, nil
+}
+
+// UnreadByte implements io.ByteScanner by moving the read cursor back one byte.
+func (b *Buffer) UnreadByte() error {
+	if b.off <= 0 {
+		return ErrUnreadByte
+	}
+
+	b.off = min(b.off, len(b.B)) - 1
+	return nil
+}
+
+// Next returns a slice of the next n unread bytes (or all unread bytes if fewer), and
+// advances the read cursor past them. The slice is only valid until the next write.
+func (b *Buffer) Next(n int) []byte {
+	start := b.Offset()
+	end := start + min(max(n, 0), len(b.B)-start)
+	b.off = end
+	return b.B[start:end:end]
+}
+
+// Seek implements io.Seeker by moving the read cursor. The cursor can't be moved outside B.
+func (b *Buffer) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += int64(b.Offset())
+	case io.SeekEnd:
+		offset += int64(len(b.B))
+	default:
+		return int64(b.Offset()), ErrInvalidValue
+	}
+
+	if offset < 0 || offset > int64(len(b.B)) {
+		return int64(b.Offset()), ErrInvalidOffset
+	}
+
+	b.off = int(offset)
+	return offset, nil
+}
+
+// ReadFromLimit is like ReadFrom, but reads at most limit bytes. If r has more than limit
+// bytes, the first limit bytes are kept and ErrTooLarge is returned.
+func (b *Buffer) ReadFromLimit(r io.Reader, limit int64) (n int64, err error) {
+	if limit < 0 {
+		return 0, ErrNegativeCount
+	}
+
+	// No buffer can hold more than math.MaxInt64 bytes anyway, and adding one would overflow.
+	if limit == math.MaxInt64 {
+		return b.ReadFrom(r)
+	}
+
+	// Read one byte past the limit to tell whether r has more.
+	if n, err = b.ReadFrom(io.LimitReader(r, limit+1)); err != nil {
+		return
+	}
+
+	if n > limit {
+		b.B = b.B[:len(b.B)-int(n-limit)]
+		return limit, ErrTooLarge
+	}
+
+	return
+}
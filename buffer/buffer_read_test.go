@@ -0,0 +1,93 @@
+package buffer
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBuffer_Read(t *testing.T) {
+	b := NewBuffer(0)
+	b.WriteString("hello, world")
+
+	if err := iotest.TestReader(b, []byte("hello, world")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := string(b.Next(5)); s != "hello" {
+		t.Errorf("expected %q, got %q", "hello", s)
+	}
+
+	if c, _ := b.ReadByte(); c != ',' {
+		t.Errorf("expected ',', got %q", c)
+	}
+
+	if err := b.UnreadByte(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Offset() != 5 {
+		t.Errorf("expected offset 5, got %d", b.Offset())
+	}
+
+	if off, err := b.Seek(-5, io.SeekEnd); err != nil || off != 7 {
+		t.Errorf("expected offset 7, got %d (%v)", off, err)
+	}
+
+	if _, err := b.Seek(1, io.SeekEnd); !errors.Is(err, ErrInvalidOffset) {
+		t.Errorf("expected ErrInvalidOffset, got %v", err)
+	}
+
+	var w strings.Builder
+
+	if _, err := b.WriteTo(&w); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.String() != "world" {
+		t.Errorf("expected the unread bytes to be written, got %q", w.String())
+	}
+
+	if b.Len() != 0 || b.Offset() != 0 {
+		t.Errorf("expected an empty buffer, got %d bytes at offset %d", b.Len(), b.Offset())
+	}
+
+	if err := b.UnreadByte(); !errors.Is(err, ErrUnreadByte) {
+		t.Errorf("expected ErrUnreadByte, got %v", err)
+	}
+
+	if s := b.Next(10); len(s) != 0 {
+		t.Errorf("expected nothing, got %q", s)
+	}
+}
+
+func TestBuffer_ReadFromLimit(t *testing.T) {
+	var b Buffer
+
+	if n, err := b.ReadFromLimit(strings.NewReader("foobar"), 6); err != nil || n != 6 {
+		t.Fatalf("expected 6 bytes, got %d (%v)", n, err)
+	}
+
+	b.Reset()
+
+	if n, err := b.ReadFromLimit(strings.NewReader("foobar"), 3); !errors.Is(err, ErrTooLarge) || n != 3 {
+		t.Fatalf("expected ErrTooLarge after 3 bytes, got %d (%v)", n, err)
+	}
+
+	if b.String() != "foo" {
+		t.Errorf("expected %q, got %q", "foo", b.String())
+	}
+
+	b.Reset()
+
+	if n, err := b.ReadFromLimit(strings.NewReader("foobar"), math.MaxInt64); err != nil || n != 6 {
+		t.Fatalf("expected 6 bytes without a limit, got %d (%v)", n, err)
+	}
+}
@@ -0,0 +1,199 @@
+package buffer
+
+import (
+	"io"
+	"net"
+	"slices"
+)
+
+var (
+	_ io.Writer       = (*Chain)(nil)
+	_ io.ByteWriter   = (*Chain)(nil)
+	_ io.StringWriter = (*Chain)(nil)
+	_ io.WriterTo     = (*Chain)(nil)
+)
+
+// DefaultChunkSize is the chunk size of a Chain created without one.
+const DefaultChunkSize = 16 << 10
+
+var chunkPool = Pool{SizeClassed: true}
+
+// A Chain is a byte buffer made of pooled, fixed-size chunks. Unlike a Buffer, it never
+// copies its contents when it grows, which makes it suitable for large payloads. The zero
+// value is an empty Chain with the default chunk size.
+type Chain struct {
+	chunks    []*Buffer
+	bufs      net.Buffers
+	str       Buffer // Written by Str, and moved into the chunks by the next call
+	chunkSize int
+}
+
+// NewChain creates an empty Chain of chunks with a capacity of at least chunkSize bytes. A
+// chunkSize of 0 or less means DefaultChunkSize.
+func NewChain(chunkSize int) *Chain {
+	return &Chain{
+		chunkSize: chunkSize,
+	}
+}
+
+// Len returns the number of bytes in the chain.
+func (c *Chain) Len() (n int) {
+	for _, b := range c.chunks {
+		n += len(b.B) - b.Offset()
+	}
+
+	return n + len(c.str.B)
+}
+
+// Reset empties the chain and releases its chunks to the pool.
+func (c *Chain) Reset() {
+	for _, b := range c.chunks {
+		chunkPool.Put(b)
+	}
+
+	clear(c.chunks)
+	clear(c.bufs)
+	c.chunks = c.chunks[:0]
+	c.bufs = c.bufs[:0]
+	c.str.Reset()
+}
+
+// Str returns a StringBuffer appending to the chain. As a StringBuffer can't roll over into
+// new chunks, it appends to a separate buffer, which is copied into the chunks by the next
+// call on the chain. The StringBuffer is only valid until then.
+func (c *Chain) Str() StringBuffer {
+	c.flush()
+	return StringBuffer{B: &c.str}
+}
+
+// Write implements io.Writer.
+func (c *Chain) Write(p []byte) (n int, err error) {
+	c.flush()
+	c.write(p)
+	return len(p), nil
+}
+
+func (c *Chain) write(p []byte) {
+	b := c.tail()
+
+	for {
+		m := min(len(p), cap(b.B)-len(b.B))
+		b.B = append(b.B, p[:m]...)
+		p = p[m:]
+
+		if len(p) == 0 {
+			return
+		}
+
+		b = c.grow()
+	}
+}
+
+// WriteByte implements io.ByteWriter.
+func (c *Chain) WriteByte(v byte) error {
+	c.flush()
+	b := c.tail()
+
+	if len(b.B) == cap(b.B) {
+		b = c.grow()
+	}
+
+	b.B = append(b.B, v)
+	return nil
+}
+
+// WriteString implements io.StringWriter.
+func (c *Chain) WriteString(s string) (n int, err error) {
+	c.flush()
+	n = len(s)
+	b := c.tail()
+
+	for {
+		m := min(len(s), cap(b.B)-len(b.B))
+		b.B = append(b.B, s[:m]...)
+		s = s[m:]
+
+		if len(s) == 0 {
+			return
+		}
+
+		b = c.grow()
+	}
+}
+
+// WriteTo implements io.WriterTo. All chunks are written at once with net.Buffers, which
+// results in a single writev system call on connections that support it. The chain is reset
+// on success, and only keeps the bytes that weren't written on failure.
+func (c *Chain) WriteTo(w io.Writer) (int64, error) {
+	c.flush()
+	c.bufs = c.bufs[:0]
+
+	for _, b := range c.chunks {
+		if p := b.B[b.Offset():]; len(p) > 0 {
+			c.bufs = append(c.bufs, p)
+		}
+	}
+
+	// net.Buffers consumes itself as it's written, so write a copy of the header.
+	bufs := c.bufs
+	n, err := bufs.WriteTo(w)
+
+	if err == nil {
+		c.Reset()
+	} else {
+		clear(c.bufs)
+		c.discard(n)
+	}
+
+	return n, err
+}
+
+// flush moves the bytes written by Str into the chunks.
+func (c *Chain) flush() {
+	if len(c.str.B) > 0 {
+		c.write(c.str.B)
+		c.str.Reset()
+	}
+}
+
+// discard drops the first n bytes of the chain, and releases the chunks that are left empty.
+func (c *Chain) discard(n int64) {
+	i := 0
+
+	for ; i < len(c.chunks); i++ {
+		b := c.chunks[i]
+		m := int64(len(b.B) - b.Offset())
+
+		if m > n {
+			b.Next(int(n))
+			break
+		}
+
+		n -= m
+		chunkPool.Put(b)
+	}
+
+	c.chunks = slices.Delete(c.chunks, 0, i)
+}
+
+// tail returns the current chunk, which is created if there is none.
+func (c *Chain) tail() *Buffer {
+	if len(c.chunks) == 0 {
+		return c.grow()
+	}
+
+	return c.chunks[len(c.chunks)-1]
+}
+
+// grow appends a new chunk and returns it.
+func (c *Chain) grow() *Buffer {
+	size := c.chunkSize
+
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+
+	b := chunkPool.GetSize(size)
+	c.chunks = append(c.chunks, b)
+	return b
+}
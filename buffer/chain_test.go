@@ -0,0 +1,178 @@
+package buffer
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	c := NewChain(64)
+	exp := NewBuffer(0)
+
+	for i := range 100 {
+		s := strings.Repeat("x", i)
+		c.WriteString(s)
+		c.Write([]byte(s))
+		c.WriteByte('.')
+		c.Str().WriteInt(i)
+		exp.WriteString(s)
+		exp.WriteString(s)
+		exp.WriteByte('.')
+		exp.Str().WriteInt(i)
+	}
+
+	if c.Len() != exp.Len() {
+		t.Fatalf("expected length %d, got %d", exp.Len(), c.Len())
+	}
+
+	for i, b := range c.chunks {
+		if b.Cap() != 64 {
+			t.Fatalf("chunk %d: expected capacity 64, got %d", i, b.Cap())
+		}
+	}
+
+	var got bytes.Buffer
+
+	if _, err := c.WriteTo(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.String() != exp.String() {
+		t.Errorf("expected %q, got %q", exp.String(), got.String())
+	}
+
+	if c.Len() != 0 {
+		t.Errorf("expected an empty chain after WriteTo, got %d bytes", c.Len())
+	}
+}
+
+func TestChain_Str(t *testing.T) {
+	c := NewChain(64)
+	c.WriteString(strings.Repeat("x", 60))
+
+	// More than the space left in the chunk, which must not be grown.
+	c.Str().WriteString(strings.Repeat("y", 100))
+	c.Str().WriteInt(123)
+
+	if c.Len() != 163 {
+		t.Fatalf("expected length 163, got %d", c.Len())
+	}
+
+	c.WriteByte('.')
+
+	for i, b := range c.chunks {
+		if b.Cap() != 64 {
+			t.Fatalf("chunk %d: expected capacity 64, got %d", i, b.Cap())
+		}
+	}
+
+	var got bytes.Buffer
+
+	if _, err := c.WriteTo(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := strings.Repeat("x", 60) + strings.Repeat("y", 100) + "123."; got.String() != exp {
+		t.Errorf("expected %q, got %q", exp, got.String())
+	}
+}
+
+var errChainShort = errors.New("short write")
+
+// shortChainWriter accepts at most n bytes in total.
+type shortChainWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (w *shortChainWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n, _ := w.Buffer.Write(p[:w.n])
+		w.n = 0
+		return n, errChainShort
+	}
+
+	w.n -= len(p)
+	return w.Buffer.Write(p)
+}
+
+func TestChain_WriteTo_Partial(t *testing.T) {
+	exp := strings.Repeat("0123456789", 30)
+
+	for _, written := range []int{0, 30, 64, 100, 299} {
+		c := NewChain(64)
+		c.WriteString(exp)
+		w := &shortChainWriter{n: written}
+
+		if n, err := c.WriteTo(w); err != errChainShort || n != int64(written) {
+			t.Fatalf("expected %d bytes and errChainShort, got %d and %v", written, n, err)
+		}
+
+		if c.Len() != len(exp)-written {
+			t.Fatalf("expected %d bytes left, got %d", len(exp)-written, c.Len())
+		}
+
+		// Retrying must not resend what was already written.
+		w.n = len(exp)
+
+		if _, err := c.WriteTo(w); err != nil {
+			t.Fatal(err)
+		}
+
+		if w.String() != exp {
+			t.Errorf("after %d bytes: expected %q, got %q", written, exp, w.String())
+		}
+	}
+}
+
+func TestChain_WriteTo_Conn(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	var c Chain
+	c.WriteString(strings.Repeat("foo", DefaultChunkSize))
+	exp := c.Len()
+
+	go func() {
+		defer server.Close()
+		c.WriteTo(server)
+	}()
+
+	var got bytes.Buffer
+
+	if _, err := got.ReadFrom(client); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Len() != exp {
+		t.Errorf("expected %d bytes, got %d", exp, got.Len())
+	}
+}
+
+func BenchmarkChain_Write(b *testing.B) {
+	data := make([]byte, 1000)
+	var c Chain
+
+	for range b.N {
+		for range 1000 {
+			c.Write(data)
+		}
+
+		c.Reset()
+	}
+}
+
+func BenchmarkBuffer_Write(b *testing.B) {
+	data := make([]byte, 1000)
+
+	for range b.N {
+		var buf Buffer
+
+		for range 1000 {
+			buf.Write(data)
+		}
+	}
+}
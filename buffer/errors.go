@@ -6,4 +6,7 @@ var (
 	ErrNegativeCount = errors.New("negative count")
 	ErrInvalidValue  = errors.New("invalid value")
 	ErrFewArgs       = errors.New("too few arguments")
+	ErrInvalidOffset = errors.New("invalid offset")
+	ErrTooLarge      = errors.New("too large")
+	ErrUnreadByte    = errors.New("nothing to unread")
 )
@@ -3,6 +3,8 @@ package buffer
 import (
 	"sync"
 	"sync/atomic"
+
+	"github.com/webmafia/fast"
 )
 
 const (
@@ -21,14 +23,28 @@ const (
 // Distinct pools may be used for distinct types of byte buffers.
 // Properly determined byte buffer types with their own pools may help reducing
 // memory waste.
+//
+// A pool serving payloads of widely different sizes should set SizeClassed, and get its
+// buffers with GetSize.
 type Pool struct {
+	gets  atomic.Uint64
+	news  atomic.Uint64
+	drops atomic.Uint64
+	puts  [steps]atomic.Uint64
+
+	// SizeClassed makes the pool keep one sync.Pool per power-of-two size class instead of
+	// a single calibrated one. It must be set before the pool is used.
+	SizeClassed bool
+
 	calls       [steps]uint32
 	calibrating uint32
 
 	defaultSize uint32
 	maxSize     uint32
 
-	pool sync.Pool
+	pool    sync.Pool
+	classes [steps]sync.Pool
+	tracker *fast.Tracker[Buffer]
 }
 
 // Get returns new byte buffer with zero length.
@@ -36,25 +52,69 @@ type Pool struct {
 // The byte buffer may be returned to the pool via Put after the use
 // in order to minimize GC overhead.
 func (p *Pool) Get() *Buffer {
+	if p.SizeClassed {
+		return p.GetSize(minSize)
+	}
+
+	p.gets.Add(1)
+
 	v := p.pool.Get()
 	if v != nil {
-		return v.(*Buffer)
+		return p.acquired(v.(*Buffer), true)
 	}
 
+	p.news.Add(1)
+
 	size := int(atomic.LoadUint32(&p.defaultSize))
 
 	if size == 0 {
 		size = 64
 	}
 
-	return NewBuffer(size)
+	return p.acquired(NewBuffer(size), false)
+}
+
+// GetSize returns new byte buffer with zero length and a capacity of at least hint bytes.
+//
+// In a size-classed pool, the buffer is taken from the smallest size class that fits hint.
+func (p *Pool) GetSize(hint int) *Buffer {
+	if !p.SizeClassed {
+		b := p.Get()
+		_ = b.Grow(hint)
+		return b
+	}
+
+	p.gets.Add(1)
+
+	if hint <= maxSize {
+		if v := p.classes[index(hint)].Get(); v != nil {
+			return p.acquired(v.(*Buffer), true)
+		}
+	}
+
+	p.news.Add(1)
+	return p.acquired(NewBuffer(max(minSize, roundPow(hint))), false)
 }
 
-// Put releases byte buffer obtained via Get to the pool.
+// Put releases byte buffer obtained via Get or GetSize to the pool.
 //
 // The buffer mustn't be accessed after returning to the pool.
 func (p *Pool) Put(b *Buffer) {
 	idx := index(cap(b.B))
+	p.puts[idx].Add(1)
+
+	if p.tracker != nil {
+		if !p.tracker.Release(b) {
+			return
+		}
+
+		poison(b.B[:cap(b.B)])
+	}
+
+	if p.SizeClassed {
+		p.putClassed(b)
+		return
+	}
 
 	if atomic.AddUint32(&p.calls[idx], 1) > calibrateCallsThreshold {
 		p.calibrate()
@@ -64,7 +124,30 @@ func (p *Pool) Put(b *Buffer) {
 	if maxSize == 0 || cap(b.B) <= maxSize {
 		b.Reset()
 		p.pool.Put(b)
+	} else {
+		p.drops.Add(1)
+	}
+}
+
+// putClassed routes b to the largest size class that its capacity fills, so that every
+// buffer in a class is at least as large as the class size. Buffers outside the size
+// classes are dropped.
+func (p *Pool) putClassed(b *Buffer) {
+	c := cap(b.B)
+
+	if c < minSize || c > maxSize {
+		p.drops.Add(1)
+		return
 	}
+
+	idx := index(c)
+
+	if minSize<<idx > c {
+		idx--
+	}
+
+	b.Reset()
+	p.classes[idx].Put(b)
 }
 
 func (p *Pool) calibrate() {
@@ -0,0 +1,47 @@
+package buffer
+
+import "github.com/webmafia/fast"
+
+// Released buffers are filled with poisonByte in debug mode.
+const poisonByte = 0xdd
+
+// SetDebug enables debug mode, in which the pool tracks its buffers to detect buffers put
+// twice, not gotten from the pool (e.g. a Buffer embedded in another struct) or never put
+// back, and reports them to report (see fast.NewTracker). Put buffers are filled with
+// garbage, which is verified when the buffer is reused, to also detect writes to a buffer
+// after it was put. It must be called before the pool is used.
+func (p *Pool) SetDebug(report func(*fast.PoolError)) {
+	p.tracker = fast.NewTracker[Buffer](report)
+}
+
+// acquired tracks b in debug mode, and verifies that a pooled buffer hasn't been written
+// to since it was put.
+func (p *Pool) acquired(b *Buffer, pooled bool) *Buffer {
+	if p.tracker == nil {
+		return b
+	}
+
+	if pooled && !poisoned(b.B[:cap(b.B)]) {
+		p.tracker.UsedAfterRelease(b)
+	}
+
+	p.tracker.Acquire(b)
+	b.Reset()
+	return b
+}
+
+func poison(b []byte) {
+	for i := range b {
+		b[i] = poisonByte
+	}
+}
+
+func poisoned(b []byte) bool {
+	for _, c := range b {
+		if c != poisonByte {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,59 @@
+package buffer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/webmafia/fast"
+)
+
+func TestPool_Debug(t *testing.T) {
+	for _, classed := range []bool{false, true} {
+		var reported []error
+		p := Pool{SizeClassed: classed}
+		p.SetDebug(func(err *fast.PoolError) {
+			reported = append(reported, err)
+		})
+
+		buf := p.Get()
+		buf.B = append(buf.B, "foo"...)
+		p.Put(buf)
+		p.Put(buf)
+
+		if len(reported) != 1 || !errors.Is(reported[0], fast.ErrDoubleRelease) {
+			t.Fatalf("classed=%v: expected a double release, got %v", classed, reported)
+		}
+
+		if buf.B[:cap(buf.B)][0] != poisonByte {
+			t.Fatalf("classed=%v: expected a poisoned buffer, got %x", classed, buf.B[:3])
+		}
+
+		// Write to the buffer after it's been put, and reuse it. The sync.Pool is bypassed, as
+		// it might drop the buffer.
+		buf.B = append(buf.B, "bar"...)
+		p.acquired(buf, true)
+
+		if len(reported) != 2 || !errors.Is(reported[1], fast.ErrUseAfterRelease) {
+			t.Fatalf("classed=%v: expected a use after release, got %v", classed, reported)
+		}
+	}
+}
+
+func TestPool_Debug_Embedded(t *testing.T) {
+	var reported []error
+	var p Pool
+	p.SetDebug(func(err *fast.PoolError) {
+		reported = append(reported, err)
+	})
+
+	var h struct {
+		id  int
+		buf Buffer
+	}
+
+	p.Put(&h.buf)
+
+	if len(reported) != 1 || !errors.Is(reported[0], fast.ErrForeign) {
+		t.Errorf("expected a foreign buffer, got %v", reported)
+	}
+}
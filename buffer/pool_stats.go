@@ -0,0 +1,55 @@
+package buffer
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// PoolStats holds the counters of a Pool since it was created, and its current
+// calibration.
+type PoolStats struct {
+	Gets  uint64 // Number of calls to Get and GetSize
+	Puts  uint64 // Number of calls to Put
+	News  uint64 // Number of buffers allocated because the pool was empty
+	Drops uint64 // Number of buffers not kept by Put, because they were too small or too large
+
+	DefaultSize int // Capacity of buffers allocated by Get, or 0 if not yet calibrated
+	MaxSize     int // Largest capacity kept by Put, or 0 if not yet calibrated
+
+	// Calls[i] is the number of buffers put back with a capacity of up to 64<<i bytes
+	// (but more than the previous class). The last class also counts all larger buffers.
+	Calls [steps]uint64
+}
+
+// HitRate returns the share of gets that were served by a pooled buffer.
+func (s PoolStats) HitRate() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+
+	return 1 - float64(s.News)/float64(s.Gets)
+}
+
+// Stats returns a snapshot of the pool's counters and calibration.
+func (p *Pool) Stats() (s PoolStats) {
+	s.Gets = p.gets.Load()
+	s.News = p.news.Load()
+	s.Drops = p.drops.Load()
+	s.DefaultSize = int(atomic.LoadUint32(&p.defaultSize))
+	s.MaxSize = int(atomic.LoadUint32(&p.maxSize))
+
+	for i := range s.Calls {
+		s.Calls[i] = p.puts[i].Load()
+		s.Puts += s.Calls[i]
+	}
+
+	return
+}
+
+// Publish exports the pool's stats as an expvar variable with the given name. Like
+// expvar.Publish, it panics if the name is already in use.
+func (p *Pool) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return p.Stats()
+	}))
+}
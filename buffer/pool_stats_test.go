@@ -0,0 +1,52 @@
+package buffer
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestPool_Stats(t *testing.T) {
+	var p Pool
+
+	buf := p.Get()
+	p.Put(buf)
+	p.Get()
+	p.Put(NewBuffer(1000))
+
+	s := p.Stats()
+
+	if s.Gets != 2 || s.Puts != 2 || s.News < 1 || s.News > 2 || s.Drops != 0 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+
+	if s.Calls[0] != 1 || s.Calls[index(1000)] != 1 {
+		t.Errorf("unexpected histogram: %v", s.Calls)
+	}
+}
+
+func TestPool_Stats_Drops(t *testing.T) {
+	p := Pool{SizeClassed: true}
+	p.Put(NewBuffer(maxSize * 2))
+	p.Put(&Buffer{})
+
+	if s := p.Stats(); s.Drops != 2 || s.Calls[steps-1] != 1 || s.Calls[0] != 1 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+}
+
+func TestPool_Publish(t *testing.T) {
+	var p Pool
+	p.Get()
+	p.Publish("buffer_test_pool")
+
+	var s PoolStats
+
+	if err := json.Unmarshal([]byte(expvar.Get("buffer_test_pool").String()), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Gets != 1 {
+		t.Errorf("expected 1 get, got %d", s.Gets)
+	}
+}
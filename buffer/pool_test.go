@@ -4,6 +4,37 @@ import (
 	"testing"
 )
 
+func TestPool_GetSize(t *testing.T) {
+	for _, classed := range []bool{false, true} {
+		p := Pool{SizeClassed: classed}
+
+		for _, hint := range []int{0, 1, 100, 200, 64 << 10, maxSize + 1} {
+			buf := p.GetSize(hint)
+
+			if buf.Len() != 0 || buf.Cap() < hint {
+				t.Fatalf("classed=%v, hint %d: got len %d, cap %d", classed, hint, buf.Len(), buf.Cap())
+			}
+
+			buf.B = append(buf.B, "foo"...)
+			p.Put(buf)
+		}
+	}
+}
+
+func TestPool_SizeClassed(t *testing.T) {
+	p := Pool{SizeClassed: true}
+
+	// A capacity between two classes must go to the lower one, so that it isn't handed
+	// out for hints it can't hold.
+	p.Put(NewBuffer(200))
+
+	for range 100 {
+		if buf := p.GetSize(129); buf.Cap() < 129 {
+			t.Fatalf("expected a capacity of at least 129, got %d", buf.Cap())
+		}
+	}
+}
+
 func Benchmark_calibrate(b *testing.B) {
 	var p Pool
 
@@ -25,6 +56,17 @@ func BenchmarkPool(b *testing.B) {
 	}
 }
 
+func BenchmarkPool_SizeClassed(b *testing.B) {
+	p := Pool{SizeClassed: true}
+	sizes := [...]int{100, 64 << 10}
+
+	for i := range b.N {
+		buf := p.GetSize(sizes[i%len(sizes)])
+		buf.B = append(buf.B, "foo"...)
+		p.Put(buf)
+	}
+}
+
 func BenchmarkPool_Parallell(b *testing.B) {
 	b.RunParallel(func(p *testing.PB) {
 		var pool Pool
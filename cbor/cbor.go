@@ -0,0 +1,172 @@
+// Package cbor implements the Concise Binary Object Representation (RFC 8949) on top of
+// binary.BufferWriter and binary.BufferReader. Writers always use the preferred (shortest)
+// serialization, and can optionally produce the core deterministic encoding. Readers decode
+// one token at a time, and return byte and text strings without copying.
+package cbor
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrMalformed    = errors.New("malformed cbor")
+	ErrInvalidType  = errors.New("invalid cbor type")
+	ErrTooLarge     = errors.New("length too large")
+	ErrTooDeep      = errors.New("cbor nested too deep")
+	ErrIndefinite   = errors.New("indefinite length in deterministic encoding")
+	ErrDuplicateKey = errors.New("duplicate map key")
+)
+
+// MaxDepth is the maximum nesting of arrays, maps and indefinite-length strings that Skip
+// accepts.
+const MaxDepth = 1024
+
+// Major types.
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorString = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// Additional information values.
+const (
+	info8          = 24
+	info16         = 25
+	info32         = 26
+	info64         = 27
+	infoIndefinite = 31
+
+	simpleFalse     = 20
+	simpleTrue      = 21
+	simpleNull      = 22
+	simpleUndefined = 23
+
+	breakByte = 0xff
+)
+
+// Kind is the kind of a Token.
+type Kind uint8
+
+const (
+	Uint Kind = iota
+	NegInt
+	Bytes
+	String
+	Array
+	Map
+	Tag
+	Simple
+	Bool
+	Null
+	Undefined
+	Float
+	Break
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Uint:
+		return "uint"
+	case NegInt:
+		return "negative int"
+	case Bytes:
+		return "bytes"
+	case String:
+		return "string"
+	case Array:
+		return "array"
+	case Map:
+		return "map"
+	case Tag:
+		return "tag"
+	case Simple:
+		return "simple"
+	case Bool:
+		return "bool"
+	case Null:
+		return "null"
+	case Undefined:
+		return "undefined"
+	case Float:
+		return "float"
+	case Break:
+		return "break"
+	}
+
+	return "unknown"
+}
+
+// float16Bits returns the half-precision bits of f, and whether f can be represented as
+// such without loss.
+func float16Bits(f float32) (uint16, bool) {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23) & 0xff
+	mant := b & 0x7fffff
+
+	switch {
+	case exp == 0xff:
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+
+		return sign | 0x7c00 | uint16(mant>>13), true
+
+	case exp == 0 && mant == 0:
+		return sign, true
+	}
+
+	e := exp - 127
+
+	// Normal half-precision numbers.
+	if e >= -14 && e <= 15 {
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+
+		return sign | uint16(e+15)<<10 | uint16(mant>>13), true
+	}
+
+	// Subnormal half-precision numbers, which are multiples of 2^-24.
+	if e >= -24 && e < -14 {
+		full := 0x800000 | mant
+		shift := uint(-e - 1)
+
+		if full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+
+		return sign | uint16(full>>shift), true
+	}
+
+	return 0, false
+}
+
+func float16Value(h uint16) (v float64) {
+	exp, mant := int(h>>10)&0x1f, float64(h&0x3ff)
+
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+
+	if h&0x8000 != 0 {
+		v = -v
+	}
+
+	return
+}
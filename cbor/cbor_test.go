@@ -0,0 +1,265 @@
+package cbor
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/webmafia/fast/binary"
+)
+
+// Examples from RFC 8949 appendix A.
+var examples = []struct {
+	val any
+	hex string
+}{
+	{0, "00"},
+	{1, "01"},
+	{10, "0a"},
+	{23, "17"},
+	{24, "1818"},
+	{100, "1864"},
+	{1000, "1903e8"},
+	{1000000, "1a000f4240"},
+	{uint64(1000000000000), "1b000000e8d4a51000"},
+	{uint64(math.MaxUint64), "1bffffffffffffffff"},
+	{-1, "20"},
+	{-10, "29"},
+	{-100, "3863"},
+	{-1000, "3903e7"},
+	{0.0, "f90000"},
+	{math.Copysign(0, -1), "f98000"},
+	{1.0, "f93c00"},
+	{1.1, "fb3ff199999999999a"},
+	{1.5, "f93e00"},
+	{65504.0, "f97bff"},
+	{100000.0, "fa47c35000"},
+	{3.4028234663852886e+38, "fa7f7fffff"},
+	{1.0e+300, "fb7e37e43c8800759c"},
+	{5.960464477539063e-8, "f90001"},
+	{0.00006103515625, "f90400"},
+	{-4.0, "f9c400"},
+	{-4.1, "fbc010666666666666"},
+	{math.Inf(1), "f97c00"},
+	{math.NaN(), "f97e00"},
+	{math.Inf(-1), "f9fc00"},
+	{false, "f4"},
+	{true, "f5"},
+	{nil, "f6"},
+	{[]byte{}, "40"},
+	{[]byte{1, 2, 3, 4}, "4401020304"},
+	{"", "60"},
+	{"a", "6161"},
+	{"IETF", "6449455446"},
+	{"\"\\", "62225c"},
+	{"ü", "62c3bc"},
+	{"水", "63e6b0b4"},
+	{[]any{}, "80"},
+	{[]any{1, 2, 3}, "83010203"},
+	{[]any{1, []any{2, 3}, []any{4, 5}}, "8301820203820405"},
+	{map[string]any{}, "a0"},
+	{map[string]any{"a": 1, "b": []any{2, 3}}, "a26161016162820203"},
+	{map[string]any{"a": "A", "b": "B", "c": "C", "d": "D", "e": "E"}, "a56161614161626142616361436164614461656145"},
+}
+
+func TestWriter(t *testing.T) {
+	for _, ex := range examples {
+		w := binary.NewBufferWriter(64)
+
+		if err := NewDeterministicWriter(w).WriteVal(ex.val); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := hex.EncodeToString(w.Bytes()); got != ex.hex {
+			t.Errorf("%v: expected %s, got %s", ex.val, ex.hex, got)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	for _, ex := range examples {
+		data, _ := hex.DecodeString(ex.hex)
+		br := binary.NewBufferReader(data)
+
+		if err := NewReader(br).Skip(); err != nil {
+			t.Errorf("%s: %v", ex.hex, err)
+		}
+
+		if br.Len() != 0 {
+			t.Errorf("%s: expected the item to be consumed, got %d bytes left", ex.hex, br.Len())
+		}
+	}
+}
+
+func TestReader_Values(t *testing.T) {
+	// 1(1363896240), 24(h'6449455446'), -2^64, [_ "strea", "ming"], (_ h'0102', h'030405')
+	data, _ := hex.DecodeString("c11a514b67b0d818456449455446" + "3bffffffffffffffff" + "7f657374726561646d696e67ff" + "5f42010243030405ff")
+	r := NewReader(binary.NewBufferReader(data))
+
+	if tag, err := r.ReadTag(); err != nil || tag != 1 {
+		t.Fatalf("expected tag 1, got %d (%v)", tag, err)
+	}
+
+	if v, err := r.ReadInt(); err != nil || v != 1363896240 {
+		t.Fatalf("expected 1363896240, got %d (%v)", v, err)
+	}
+
+	if tag, err := r.ReadTag(); err != nil || tag != 24 {
+		t.Fatalf("expected tag 24, got %d (%v)", tag, err)
+	}
+
+	if b, err := r.ReadBytes(); err != nil || string(b) != "dIETF" {
+		t.Fatalf("expected 'dIETF', got '%s' (%v)", b, err)
+	}
+
+	if _, err := r.ReadInt(); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+
+	if s, err := r.ReadString(); err != nil || s != "streaming" {
+		t.Fatalf("expected 'streaming', got '%s' (%v)", s, err)
+	}
+
+	if b, err := r.ReadBytes(); err != nil || string(b) != "\x01\x02\x03\x04\x05" {
+		t.Fatalf("expected 0102030405, got %x (%v)", b, err)
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestIndefinite(t *testing.T) {
+	w := binary.NewBufferWriter(64)
+	cw := NewWriter(w)
+	cw.BeginIndefiniteMap()
+	cw.WriteString("a")
+	cw.WriteInt(1)
+	cw.WriteString("b")
+	cw.BeginIndefiniteArray()
+	cw.WriteInt(2)
+	cw.WriteInt(3)
+	cw.WriteBreak()
+	cw.WriteBreak()
+
+	// {_ "a": 1, "b": [_ 2, 3]}
+	if got, expected := hex.EncodeToString(w.Bytes()), "bf61610161629f0203ffff"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	r := NewReader(binary.NewBufferReader(w.Bytes()))
+
+	if n, err := r.ReadMapHeader(); err != nil || n != -1 {
+		t.Fatalf("expected an indefinite map, got %d (%v)", n, err)
+	}
+
+	var keys []string
+
+	for {
+		if ok, err := r.ReadBreak(); err != nil {
+			t.Fatal(err)
+		} else if ok {
+			break
+		}
+
+		key, err := r.ReadString()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys = append(keys, key)
+
+		if err = r.Skip(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("expected keys a and b, got %v", keys)
+	}
+
+	if err := NewDeterministicWriter(w).BeginIndefiniteArray(); !errors.Is(err, ErrIndefinite) {
+		t.Errorf("expected ErrIndefinite, got %v", err)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	w := binary.NewBufferWriter(64)
+	cw := NewDeterministicWriter(w)
+
+	// Keys are sorted by their encoding, so shorter keys come first.
+	m, _ := cw.BeginMap(4)
+	cw.WriteString("aa")
+	cw.WriteInt(1)
+	cw.WriteInt(-1)
+	cw.WriteInt(2)
+	cw.WriteInt(10)
+	inner, _ := cw.BeginMap(2)
+	cw.WriteString("z")
+	cw.WriteNull()
+	cw.WriteString("y")
+	cw.WriteBool(true)
+	cw.EndMap(inner)
+	cw.WriteString("b")
+	cw.WriteInt(3)
+
+	if err := cw.EndMap(m); err != nil {
+		t.Fatal(err)
+	}
+
+	// {10: {"y": true, "z": null}, -1: 2, "b": 3, "aa": 1}
+	if got, expected := hex.EncodeToString(w.Bytes()), "a40aa26179f5617af6200261620362616101"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	w.Reset()
+	m, _ = cw.BeginMap(2)
+	cw.WriteString("a")
+	cw.WriteInt(1)
+	cw.WriteString("a")
+	cw.WriteInt(2)
+
+	if err := cw.EndMap(m); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey, got %v", err)
+	}
+}
+
+func TestReader_Malformed(t *testing.T) {
+	tests := []struct {
+		hex string
+		err error
+	}{
+		{"18", io.ErrUnexpectedEOF},
+		{"1c", ErrMalformed},
+		{"3f", ErrMalformed},
+		{"ff", ErrMalformed},
+		{"f801", ErrMalformed},
+		{"5a ffffffff 00", io.ErrUnexpectedEOF},
+		{"9b ffffffffffffffff", ErrTooLarge},
+		{"82 01", io.ErrUnexpectedEOF},
+		{"9f 01", io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(stripSpaces(tt.hex))
+
+		if err := NewReader(binary.NewBufferReader(data)).Skip(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.hex, tt.err, err)
+		}
+	}
+}
+
+func stripSpaces(s string) string {
+	b := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != ' ' {
+			b = append(b, s[i])
+		}
+	}
+
+	return string(b)
+}
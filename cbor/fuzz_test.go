@@ -0,0 +1,78 @@
+package cbor
+
+import (
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/webmafia/fast/binary"
+)
+
+func FuzzRoundTrip(f *testing.F) {
+	f.Add(int64(123), 456.789, "foobar", []byte{1, 2, 3})
+	f.Add(int64(-123), math.Inf(-1), "räksmörgås", []byte{})
+
+	f.Fuzz(func(t *testing.T, i int64, fl float64, s string, b []byte) {
+		w := binary.NewBufferWriter(64)
+		cw := NewWriter(w)
+		cw.WriteArrayHeader(4)
+		cw.WriteInt(i)
+		cw.WriteFloat(fl)
+		cw.WriteString(s)
+		cw.WriteBytes(b)
+
+		r := NewReader(binary.NewBufferReader(w.Bytes()))
+
+		if n, err := r.ReadArrayHeader(); err != nil || n != 4 {
+			t.Fatalf("expected 4 items, got %d (%v)", n, err)
+		}
+
+		if res, err := r.ReadInt(); err != nil || res != i {
+			t.Errorf("expected %d, got %d (%v)", i, res, err)
+		}
+
+		if res, err := r.ReadFloat(); err != nil || (res != fl && !(math.IsNaN(res) && math.IsNaN(fl))) {
+			t.Errorf("expected %v, got %v (%v)", fl, res, err)
+		}
+
+		// Invalid UTF-8 is written as-is, but rejected when read.
+		if res, err := r.ReadString(); err == nil && res != s {
+			t.Errorf("expected '%s', got '%s'", s, res)
+		}
+
+		if res, err := r.ReadBytes(); err != nil || string(res) != string(b) {
+			t.Errorf("expected %x, got %x (%v)", b, res, err)
+		}
+	})
+}
+
+func FuzzReader(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x83, 0x01, 0x02, 0x03})
+	f.Add([]byte{0xbf, 0x61, 0x61, 0x01, 0x61, 0x62, 0x9f, 0x02, 0x03, 0xff, 0xff})
+	f.Add([]byte{0x7f, 0x65, 0x73, 0x74, 0x72, 0x65, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x67, 0xff})
+	f.Add([]byte{0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		br := binary.NewBufferReader(data)
+		r := NewReader(br)
+
+		// Skipping items must never panic, and must consume input on success.
+		for br.Len() > 0 {
+			n := br.Len()
+
+			if err := r.Skip(); err != nil {
+				if !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, ErrMalformed) && !errors.Is(err, ErrTooLarge) && !errors.Is(err, ErrTooDeep) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			if br.Len() >= n {
+				t.Fatalf("expected input to be consumed, got %d of %d bytes left", br.Len(), n)
+			}
+		}
+	})
+}
@@ -0,0 +1,429 @@
+package cbor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf8"
+
+	fastbinary "github.com/webmafia/fast/binary"
+)
+
+// A Token is a single CBOR data item. Arrays, maps and indefinite-length strings are read
+// as a header, followed by their items (or chunks) as separate tokens. A tag is read as a
+// token of its own, followed by the tagged item.
+type Token struct {
+	Kind  Kind
+	Uint  uint64  // Value of an unsigned integer, argument of a negative integer (the value is -1 - Uint), tag number or simple value
+	Float float64 // Value of a float
+	Bool  bool    // Value of a bool
+	Len   int     // Length of a byte or text string, number of items of an array, or key-value pairs of a map; -1 if indefinite
+	Bytes []byte  // Contents of a byte or text string; refers to the underlying buffer
+}
+
+// A Reader reads CBOR tokens from a binary.BufferReader.
+type Reader struct {
+	r      *fastbinary.BufferReader
+	peeked bool
+	tok    Token
+	stack  []int
+}
+
+// NewReader creates a Reader reading from r.
+func NewReader(r *fastbinary.BufferReader) *Reader {
+	return &Reader{r: r}
+}
+
+// Reset resets the Reader to read from r.
+func (r *Reader) Reset(br *fastbinary.BufferReader) {
+	r.r = br
+	r.peeked = false
+}
+
+// Next reads the next token. It returns io.EOF if there are no more tokens, and
+// io.ErrUnexpectedEOF if the input ends in the middle of one.
+func (r *Reader) Next() (t Token, err error) {
+	if r.peeked {
+		r.peeked = false
+		return r.tok, nil
+	}
+
+	c, err := r.r.ReadByte()
+
+	if err != nil {
+		return
+	}
+
+	major, info := c>>5, c&0x1f
+
+	if info == infoIndefinite {
+		return indefinite(major)
+	}
+
+	arg, err := r.arg(info)
+
+	if err != nil {
+		return
+	}
+
+	switch major {
+
+	case majorUint:
+		t.Kind, t.Uint = Uint, arg
+
+	case majorNegInt:
+		t.Kind, t.Uint = NegInt, arg
+
+	case majorBytes, majorString:
+		t.Kind = Bytes
+
+		if major == majorString {
+			t.Kind = String
+		}
+
+		if t.Len, err = length(arg); err != nil {
+			return
+		}
+
+		if t.Bytes = r.r.ReadBytes(t.Len); t.Bytes == nil && t.Len > 0 {
+			err = r.error()
+		}
+
+	case majorArray:
+		t.Kind = Array
+		t.Len, err = length(arg)
+
+	case majorMap:
+		t.Kind = Map
+		t.Len, err = length(arg)
+
+	case majorTag:
+		t.Kind, t.Uint = Tag, arg
+
+	case majorSimple:
+		switch info {
+		case info16:
+			t.Kind, t.Float = Float, float16Value(uint16(arg))
+		case info32:
+			t.Kind, t.Float = Float, float64(math.Float32frombits(uint32(arg)))
+		case info64:
+			t.Kind, t.Float = Float, math.Float64frombits(arg)
+		case simpleFalse, simpleTrue:
+			t.Kind, t.Bool = Bool, info == simpleTrue
+		case simpleNull:
+			t.Kind = Null
+		case simpleUndefined:
+			t.Kind = Undefined
+		default:
+			// Two-byte simple values below 32 are not well-formed.
+			if info == info8 && arg < 32 {
+				return t, fmt.Errorf("%w: simple value %d", ErrMalformed, arg)
+			}
+
+			t.Kind, t.Uint = Simple, arg
+		}
+
+	}
+
+	return
+}
+
+func indefinite(major byte) (t Token, err error) {
+	t.Len = -1
+
+	switch major {
+	case majorBytes:
+		t.Kind = Bytes
+	case majorString:
+		t.Kind = String
+	case majorArray:
+		t.Kind = Array
+	case majorMap:
+		t.Kind = Map
+	case majorSimple:
+		t.Kind = Break
+	default:
+		err = fmt.Errorf("%w: indefinite length of major type %d", ErrMalformed, major)
+	}
+
+	return
+}
+
+// arg reads the argument of an item with the additional information info.
+func (r *Reader) arg(info byte) (v uint64, err error) {
+	if info < info8 {
+		return uint64(info), nil
+	}
+
+	if info > info64 {
+		return 0, fmt.Errorf("%w: reserved additional information %d", ErrMalformed, info)
+	}
+
+	b := r.r.ReadBytes(1 << (info - info8))
+
+	switch len(b) {
+	case 1:
+		v = uint64(b[0])
+	case 2:
+		v = uint64(binary.BigEndian.Uint16(b))
+	case 4:
+		v = uint64(binary.BigEndian.Uint32(b))
+	case 8:
+		v = binary.BigEndian.Uint64(b)
+	default:
+		err = r.error()
+	}
+
+	return
+}
+
+func (r *Reader) error() error {
+	if err := r.r.Error(); err != nil {
+		return err
+	}
+
+	return io.ErrUnexpectedEOF
+}
+
+func length(arg uint64) (int, error) {
+	if arg > math.MaxInt {
+		return 0, fmt.Errorf("%w: %d", ErrTooLarge, arg)
+	}
+
+	return int(arg), nil
+}
+
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+func (r *Reader) expect(kind Kind) (t Token, err error) {
+	if t, err = r.Next(); err == nil && t.Kind != kind {
+		err = mismatch(kind, t)
+	}
+
+	return
+}
+
+func mismatch(expected Kind, t Token) error {
+	return fmt.Errorf("%w: expected %s, got %s", ErrInvalidType, expected, t.Kind)
+}
+
+// ReadUint reads a non-negative integer.
+func (r *Reader) ReadUint() (uint64, error) {
+	t, err := r.expect(Uint)
+	return t.Uint, err
+}
+
+// ReadInt reads an integer that fits in an int64.
+func (r *Reader) ReadInt() (v int64, err error) {
+	t, err := r.Next()
+
+	if err != nil {
+		return
+	}
+
+	if t.Kind != Uint && t.Kind != NegInt {
+		return 0, mismatch(Uint, t)
+	}
+
+	if t.Uint > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: integer overflows int64", ErrTooLarge)
+	}
+
+	if t.Kind == NegInt {
+		return -1 - int64(t.Uint), nil
+	}
+
+	return int64(t.Uint), nil
+}
+
+func (r *Reader) ReadFloat() (float64, error) {
+	t, err := r.expect(Float)
+	return t.Float, err
+}
+
+func (r *Reader) ReadBool() (bool, error) {
+	t, err := r.expect(Bool)
+	return t.Bool, err
+}
+
+func (r *Reader) ReadNull() (err error) {
+	_, err = r.expect(Null)
+	return
+}
+
+// ReadBytes reads a byte string. A definite-length string refers to the underlying buffer,
+// while the chunks of an indefinite-length string are joined into a new slice.
+func (r *Reader) ReadBytes() ([]byte, error) {
+	t, err := r.expect(Bytes)
+
+	if err != nil || t.Len >= 0 {
+		return t.Bytes, err
+	}
+
+	return r.chunks(Bytes)
+}
+
+// ReadString reads a text string, which must be valid UTF-8.
+func (r *Reader) ReadString() (s string, err error) {
+	t, err := r.expect(String)
+
+	if err != nil {
+		return
+	}
+
+	b := t.Bytes
+
+	if t.Len < 0 {
+		if b, err = r.chunks(String); err != nil {
+			return
+		}
+	}
+
+	if !utf8.Valid(b) {
+		return "", fmt.Errorf("%w: invalid UTF-8", ErrInvalidType)
+	}
+
+	return string(b), nil
+}
+
+// chunks joins the definite-length chunks of an indefinite-length string.
+func (r *Reader) chunks(kind Kind) (b []byte, err error) {
+	b = []byte{}
+
+	for {
+		t, err := r.Next()
+
+		if err != nil {
+			return nil, unexpected(err)
+		}
+
+		if t.Kind == Break {
+			return b, nil
+		}
+
+		if t.Kind != kind || t.Len < 0 {
+			return nil, fmt.Errorf("%w: invalid chunk of type %s", ErrMalformed, t.Kind)
+		}
+
+		b = append(b, t.Bytes...)
+	}
+}
+
+// ReadArrayHeader reads the header of an array and returns its number of items, or -1 if
+// the array is of indefinite length and finished by a break.
+func (r *Reader) ReadArrayHeader() (int, error) {
+	t, err := r.expect(Array)
+	return t.Len, err
+}
+
+// ReadMapHeader reads the header of a map and returns its number of key-value pairs, or -1
+// if the map is of indefinite length and finished by a break.
+func (r *Reader) ReadMapHeader() (int, error) {
+	t, err := r.expect(Map)
+	return t.Len, err
+}
+
+// ReadTag reads a tag number, which applies to the next item.
+func (r *Reader) ReadTag() (uint64, error) {
+	t, err := r.expect(Tag)
+	return t.Uint, err
+}
+
+// ReadBreak reads a break and reports whether there was one. Any other token is left to be
+// read by the next call.
+func (r *Reader) ReadBreak() (ok bool, err error) {
+	t, err := r.Next()
+
+	if err != nil {
+		return false, unexpected(err)
+	}
+
+	if t.Kind == Break {
+		return true, nil
+	}
+
+	r.tok, r.peeked = t, true
+	return
+}
+
+// Skip skips the next item, including all items of an array or map and chunks of an
+// indefinite-length string. It returns io.ErrUnexpectedEOF if the input ends before the
+// item does, and ErrTooDeep if items are nested deeper than MaxDepth.
+func (r *Reader) Skip() error {
+	// Each level holds the number of items left, or -1 until a break.
+	stack := append(r.stack[:0], 1)
+	defer func() { r.stack = stack[:0] }()
+
+	for len(stack) > 0 {
+		t, err := r.Next()
+
+		if err != nil {
+			return unexpected(err)
+		}
+
+		n := 0
+
+		switch t.Kind {
+
+		case Tag:
+			// The tagged item follows.
+			continue
+
+		case Break:
+			if stack[len(stack)-1] != -1 {
+				return fmt.Errorf("%w: unexpected break", ErrMalformed)
+			}
+
+			stack = stack[:len(stack)-1]
+
+		case Array:
+			n = t.Len
+
+		case Map:
+			if n = t.Len; n > math.MaxInt/2 {
+				return fmt.Errorf("%w: %d", ErrTooLarge, n)
+			} else if n > 0 {
+				n *= 2
+			}
+
+		case Bytes, String:
+			if t.Len < 0 {
+				n = -1
+			}
+
+		}
+
+		if n != 0 {
+			if len(stack) >= MaxDepth {
+				return ErrTooDeep
+			}
+
+			stack = append(stack, n)
+			continue
+		}
+
+		// An item is done, which might finish its enclosing arrays and maps.
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			if *top < 0 {
+				break
+			}
+
+			if *top--; *top > 0 {
+				break
+			}
+
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return nil
+}
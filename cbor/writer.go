@@ -0,0 +1,360 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+
+	fastbinary "github.com/webmafia/fast/binary"
+)
+
+// A Writer writes CBOR items to a binary.BufferWriter, always in the shortest form. CBOR is
+// big-endian regardless of the byte order of the underlying writer.
+type Writer struct {
+	w             *fastbinary.BufferWriter
+	deterministic bool
+	entries       []entry
+	scratch       []byte
+	tmp           [9]byte
+}
+
+// An entry is a key-value pair of a map being sorted, as offsets into its entries.
+type entry struct {
+	key, val, end int
+}
+
+// A MapSection is a map started by BeginMap.
+type MapSection struct {
+	offset, n int
+}
+
+// NewWriter creates a Writer writing to w.
+func NewWriter(w *fastbinary.BufferWriter) *Writer {
+	return &Writer{w: w}
+}
+
+// NewDeterministicWriter creates a Writer writing the core deterministic encoding of RFC
+// 8949 section 4.2.1 to w: indefinite lengths are rejected, and the entries of maps written
+// with BeginMap and EndMap are sorted by their encoded keys.
+func NewDeterministicWriter(w *fastbinary.BufferWriter) *Writer {
+	return &Writer{w: w, deterministic: true}
+}
+
+// Reset resets the Writer to write to w.
+func (w *Writer) Reset(bw *fastbinary.BufferWriter) {
+	w.w = bw
+}
+
+func (w *Writer) write(p []byte) (err error) {
+	_, err = w.w.Write(p)
+	return
+}
+
+// head writes the initial byte of an item of a major type, followed by its argument v.
+func (w *Writer) head(major byte, v uint64) error {
+	major <<= 5
+
+	switch {
+	case v < info8:
+		return w.w.WriteUint8(major | byte(v))
+	case v <= math.MaxUint8:
+		w.tmp[0], w.tmp[1] = major|info8, byte(v)
+		return w.write(w.tmp[:2])
+	case v <= math.MaxUint16:
+		w.tmp[0] = major | info16
+		binary.BigEndian.PutUint16(w.tmp[1:], uint16(v))
+		return w.write(w.tmp[:3])
+	case v <= math.MaxUint32:
+		w.tmp[0] = major | info32
+		binary.BigEndian.PutUint32(w.tmp[1:], uint32(v))
+		return w.write(w.tmp[:5])
+	}
+
+	w.tmp[0] = major | info64
+	binary.BigEndian.PutUint64(w.tmp[1:], v)
+	return w.write(w.tmp[:9])
+}
+
+func (w *Writer) indefinite(major byte) error {
+	if w.deterministic {
+		return ErrIndefinite
+	}
+
+	return w.w.WriteUint8(major<<5 | infoIndefinite)
+}
+
+func (w *Writer) WriteUint(v uint64) error {
+	return w.head(majorUint, v)
+}
+
+func (w *Writer) WriteInt(v int64) error {
+	if v >= 0 {
+		return w.head(majorUint, uint64(v))
+	}
+
+	return w.head(majorNegInt, ^uint64(v))
+}
+
+func (w *Writer) WriteBytes(b []byte) (err error) {
+	if err = w.head(majorBytes, uint64(len(b))); err != nil {
+		return
+	}
+
+	return w.write(b)
+}
+
+func (w *Writer) WriteString(s string) (err error) {
+	if err = w.head(majorString, uint64(len(s))); err != nil {
+		return
+	}
+
+	_, err = w.w.WriteString(s)
+	return
+}
+
+// WriteArrayHeader writes the header of an array of n items, which must follow.
+func (w *Writer) WriteArrayHeader(n int) error {
+	return w.head(majorArray, uint64(n))
+}
+
+// WriteMapHeader writes the header of a map of n key-value pairs, which must follow. Use
+// BeginMap instead to get sorted keys from a deterministic Writer.
+func (w *Writer) WriteMapHeader(n int) error {
+	return w.head(majorMap, uint64(n))
+}
+
+// BeginMap writes the header of a map of n key-value pairs, which must follow before the
+// map is finished with EndMap.
+func (w *Writer) BeginMap(n int) (m MapSection, err error) {
+	if err = w.WriteMapHeader(n); err != nil {
+		return
+	}
+
+	return MapSection{offset: w.w.Len(), n: n}, nil
+}
+
+// EndMap finishes a map started by BeginMap. A deterministic Writer sorts the entries by
+// their encoded keys, and rejects duplicate keys with ErrDuplicateKey. Nested maps must be
+// ended in reverse order.
+func (w *Writer) EndMap(m MapSection) (err error) {
+	if !w.deterministic || m.n < 2 {
+		return
+	}
+
+	region := w.w.Bytes()[m.offset:]
+	br := fastbinary.NewBufferReader(region)
+	r := NewReader(br)
+	w.entries = w.entries[:0]
+
+	for range m.n {
+		var e entry
+		e.key = len(region) - br.Len()
+
+		if err = r.Skip(); err != nil {
+			return
+		}
+
+		e.val = len(region) - br.Len()
+
+		if err = r.Skip(); err != nil {
+			return
+		}
+
+		e.end = len(region) - br.Len()
+		w.entries = append(w.entries, e)
+	}
+
+	slices.SortFunc(w.entries, func(a, b entry) int {
+		return bytes.Compare(region[a.key:a.val], region[b.key:b.val])
+	})
+
+	w.scratch = w.scratch[:0]
+
+	for i, e := range w.entries {
+		if i > 0 {
+			if prev := w.entries[i-1]; bytes.Equal(region[prev.key:prev.val], region[e.key:e.val]) {
+				return fmt.Errorf("%w: %x", ErrDuplicateKey, region[e.key:e.val])
+			}
+		}
+
+		w.scratch = append(w.scratch, region[e.key:e.end]...)
+	}
+
+	copy(region, w.scratch)
+	return
+}
+
+// WriteTag writes a tag number, which applies to the next item.
+func (w *Writer) WriteTag(tag uint64) error {
+	return w.head(majorTag, tag)
+}
+
+// WriteSimple writes a simple value. Values 24 to 31 are reserved and rejected.
+func (w *Writer) WriteSimple(v uint8) error {
+	if v >= 24 && v < 32 {
+		return fmt.Errorf("%w: simple value %d", ErrInvalidType, v)
+	}
+
+	return w.head(majorSimple, uint64(v))
+}
+
+func (w *Writer) WriteBool(v bool) error {
+	if v {
+		return w.WriteSimple(simpleTrue)
+	}
+
+	return w.WriteSimple(simpleFalse)
+}
+
+func (w *Writer) WriteNull() error {
+	return w.WriteSimple(simpleNull)
+}
+
+func (w *Writer) WriteUndefined() error {
+	return w.WriteSimple(simpleUndefined)
+}
+
+// WriteFloat writes v as the shortest of a half, single or double precision float that
+// represents it without loss. NaN is always written as the half precision quiet NaN.
+func (w *Writer) WriteFloat(v float64) error {
+	if v != v {
+		w.tmp[0], w.tmp[1], w.tmp[2] = majorSimple<<5|info16, 0x7e, 0x00
+		return w.write(w.tmp[:3])
+	}
+
+	f := float32(v)
+
+	if float64(f) != v {
+		w.tmp[0] = majorSimple<<5 | info64
+		binary.BigEndian.PutUint64(w.tmp[1:], math.Float64bits(v))
+		return w.write(w.tmp[:9])
+	}
+
+	if h, ok := float16Bits(f); ok {
+		w.tmp[0] = majorSimple<<5 | info16
+		binary.BigEndian.PutUint16(w.tmp[1:], h)
+		return w.write(w.tmp[:3])
+	}
+
+	w.tmp[0] = majorSimple<<5 | info32
+	binary.BigEndian.PutUint32(w.tmp[1:], math.Float32bits(f))
+	return w.write(w.tmp[:5])
+}
+
+// BeginIndefiniteArray starts an array of unknown length, which is finished with WriteBreak.
+func (w *Writer) BeginIndefiniteArray() error {
+	return w.indefinite(majorArray)
+}
+
+// BeginIndefiniteMap starts a map of unknown length, which is finished with WriteBreak.
+func (w *Writer) BeginIndefiniteMap() error {
+	return w.indefinite(majorMap)
+}
+
+// BeginIndefiniteBytes starts a byte string of unknown length, written as chunks with
+// WriteBytes and finished with WriteBreak.
+func (w *Writer) BeginIndefiniteBytes() error {
+	return w.indefinite(majorBytes)
+}
+
+// BeginIndefiniteString starts a text string of unknown length, written as chunks with
+// WriteString and finished with WriteBreak.
+func (w *Writer) BeginIndefiniteString() error {
+	return w.indefinite(majorString)
+}
+
+// WriteBreak finishes an indefinite-length item.
+func (w *Writer) WriteBreak() error {
+	return w.w.WriteUint8(breakByte)
+}
+
+// WriteVal writes nil, a bool, integer, float, string, byte slice, []any or map[string]any.
+func (w *Writer) WriteVal(val any) (err error) {
+	switch v := val.(type) {
+
+	case nil:
+		return w.WriteNull()
+
+	case bool:
+		return w.WriteBool(v)
+
+	case int:
+		return w.WriteInt(int64(v))
+
+	case int8:
+		return w.WriteInt(int64(v))
+
+	case int16:
+		return w.WriteInt(int64(v))
+
+	case int32:
+		return w.WriteInt(int64(v))
+
+	case int64:
+		return w.WriteInt(v)
+
+	case uint:
+		return w.WriteUint(uint64(v))
+
+	case uint8:
+		return w.WriteUint(uint64(v))
+
+	case uint16:
+		return w.WriteUint(uint64(v))
+
+	case uint32:
+		return w.WriteUint(uint64(v))
+
+	case uint64:
+		return w.WriteUint(v)
+
+	case float32:
+		return w.WriteFloat(float64(v))
+
+	case float64:
+		return w.WriteFloat(v)
+
+	case string:
+		return w.WriteString(v)
+
+	case []byte:
+		return w.WriteBytes(v)
+
+	case []any:
+		if err = w.WriteArrayHeader(len(v)); err != nil {
+			return
+		}
+
+		for i := range v {
+			if err = w.WriteVal(v[i]); err != nil {
+				return
+			}
+		}
+
+		return
+
+	case map[string]any:
+		m, err := w.BeginMap(len(v))
+
+		if err != nil {
+			return err
+		}
+
+		for k, e := range v {
+			if err = w.WriteString(k); err != nil {
+				return err
+			}
+
+			if err = w.WriteVal(e); err != nil {
+				return err
+			}
+		}
+
+		return w.EndMap(m)
+
+	}
+
+	return fmt.Errorf("%w: %T", fastbinary.ErrUnknownValue, val)
+}
@@ -0,0 +1,878 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	binaryPath = "github.com/webmafia/fast/binary"
+	fastPath   = "github.com/webmafia/fast"
+
+	// fillDepth limits how deep the generated tests fill recursive types.
+	fillDepth = 3
+)
+
+// A Generator generates methods for a set of named types in a package.
+type Generator struct {
+	pkg       *types.Package
+	named     []*types.Named
+	requested map[*types.Named]bool
+	genString bool
+
+	buf     bytes.Buffer
+	imports map[string]string // path => name
+	stack   []*types.Named
+	path    []string // error wrappers of the value being decoded, outermost first
+	vars    int
+	leaves  int
+}
+
+func NewGenerator(pkg *types.Package, names []string, genString bool) (*Generator, error) {
+	g := &Generator{
+		pkg:       pkg,
+		requested: make(map[*types.Named]bool, len(names)),
+		genString: genString,
+	}
+
+	for _, name := range names {
+		obj, ok := pkg.Scope().Lookup(strings.TrimSpace(name)).(*types.TypeName)
+
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in package %s", name, pkg.Name())
+		}
+
+		named, ok := obj.Type().(*types.Named)
+
+		if !ok {
+			return nil, fmt.Errorf("%s is not a named type", name)
+		}
+
+		g.named = append(g.named, named)
+		g.requested[named] = true
+	}
+
+	return g, nil
+}
+
+func (g *Generator) reset() {
+	g.buf.Reset()
+	g.imports = make(map[string]string)
+	g.stack = g.stack[:0]
+	g.path = g.path[:0]
+	g.vars = 0
+	g.leaves = 0
+}
+
+func (g *Generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *Generator) newVar(prefix string) string {
+	g.vars++
+	return fmt.Sprintf("%s%d", prefix, g.vars)
+}
+
+func (g *Generator) use(path, name string) string {
+	g.imports[path] = name
+	return name
+}
+
+func (g *Generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+
+		return g.use(p.Path(), p.Name())
+	})
+}
+
+// source assembles the file with its imports, and formats it.
+func (g *Generator) source() ([]byte, error) {
+	var out bytes.Buffer
+
+	fmt.Fprintf(&out, "// Code generated by fastgen; DO NOT EDIT.\n\npackage %s\n\n", g.pkg.Name())
+
+	var std, other []string
+
+	for path := range g.imports {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+
+	sort.Strings(std)
+	sort.Strings(other)
+	out.WriteString("import (\n")
+
+	for _, path := range std {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+
+	if len(std) > 0 && len(other) > 0 {
+		out.WriteByte('\n')
+	}
+
+	for _, path := range other {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+
+	out.WriteString(")\n")
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+
+	if err != nil {
+		return out.Bytes(), fmt.Errorf("invalid generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// Methods generates the Encode, Decode and (optionally) EncodeString methods.
+func (g *Generator) Methods() (_ []byte, err error) {
+	g.reset()
+	binaryPkg := g.use(binaryPath, "binary")
+
+	for _, named := range g.named {
+		name := g.typeString(named)
+		v := receiver(named)
+
+		// The methods of the generated types call each other with the nesting depth, which is
+		// limited the same way as by binary.Marshal.
+		g.printf("\n// Encode implements binary.Encoder.\n")
+		g.printf("func (v *%s) Encode(w %s.Writer) (err error) {\n", name, binaryPkg)
+		g.printf("return v.fastgenEncode(w, 0)\n}\n")
+		g.printf("\nfunc (v *%s) fastgenEncode(w %s.Writer, depth int) (err error) {\n", name, binaryPkg)
+
+		if err = g.encode(v, named.Underlying()); err != nil {
+			return
+		}
+
+		g.printf("return\n}\n")
+
+		g.printf("\n// Decode implements binary.Decoder.\n")
+		g.printf("func (v *%s) Decode(r %s.Reader) (err error) {\n", name, binaryPkg)
+		g.printf("return v.fastgenDecode(r, 0)\n}\n")
+		g.printf("\nfunc (v *%s) fastgenDecode(r %s.Reader, depth int) (err error) {\n", name, binaryPkg)
+
+		if err = g.decode(v, named.Underlying()); err != nil {
+			return
+		}
+
+		g.printf("return r.Error()\n}\n")
+
+		if g.genString {
+			g.printf("\n// EncodeString implements fast.StringEncoder.\n")
+			g.printf("func (v *%s) EncodeString(b *%s.StringBuffer) {\n", name, g.use(fastPath, "fast"))
+			g.str(v, named.Underlying())
+			g.printf("}\n")
+		}
+	}
+
+	return g.source()
+}
+
+// Tests generates a round-trip test for each type.
+func (g *Generator) Tests() (_ []byte, err error) {
+	g.reset()
+	binaryPkg := g.use(binaryPath, "binary")
+	g.use("reflect", "reflect")
+	g.use("testing", "testing")
+
+	for _, named := range g.named {
+		name := g.typeString(named)
+		plain := "fastgenPlain" + named.Obj().Name()
+
+		g.printf("\nfunc Test%s_Fast(t *testing.T) {\n", named.Obj().Name())
+		g.printf("var src, dst, ref %s\n", name)
+		g.printf("fastgenFill%s(&src, 0)\n\n", named.Obj().Name())
+		g.printf("w := %s.NewBufferWriter(64)\n\n", binaryPkg)
+		g.printf("if err := src.Encode(w); err != nil {\nt.Fatal(err)\n}\n\n")
+		g.printf("if err := dst.Decode(%s.NewBufferReader(w.Bytes())); err != nil {\nt.Fatal(err)\n}\n\n", binaryPkg)
+		g.printf("if !reflect.DeepEqual(src, dst) {\nt.Errorf(\"expected %%+v, got %%+v\", src, dst)\n}\n\n")
+		g.printf("// The generated methods must be wire-compatible with binary.Marshal.\n")
+		g.printf("type %s %s\n\n", plain, name)
+		g.printf("if err := %s.Unmarshal(%s.NewBufferReader(w.Bytes()), (*%s)(&ref)); err != nil {\nt.Fatal(err)\n}\n\n", binaryPkg, binaryPkg, plain)
+		g.printf("if !reflect.DeepEqual(src, ref) {\nt.Errorf(\"expected %%+v, got %%+v\", src, ref)\n}\n")
+
+		if g.genString {
+			g.printf("\nvar b %s.StringBuffer\nsrc.EncodeString(&b)\n\n", g.use(fastPath, "fast"))
+			g.printf("if b.Len() == 0 {\nt.Error(\"expected a string encoding\")\n}\n")
+		}
+
+		g.printf("}\n")
+	}
+
+	for _, named := range g.named {
+		g.printf("\nfunc fastgenFill%s(v *%s, depth int) {\n", named.Obj().Name(), g.typeString(named))
+		g.fill(receiver(named), named.Underlying())
+		g.printf("}\n")
+	}
+
+	return g.source()
+}
+
+// receiver returns the expression of the value behind the receiver v.
+func receiver(t types.Type) string {
+	if _, ok := t.Underlying().(*types.Struct); ok {
+		return "v"
+	}
+
+	return "(*v)"
+}
+
+func hasMethod(t types.Type, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// generated reports whether t is one of the generated types.
+func (g *Generator) generated(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && g.requested[named]
+}
+
+// method reports whether t is encoded by its own method, either because it's one of the
+// generated types or because it already implements it.
+func (g *Generator) method(t types.Type, name string) bool {
+	return g.generated(t) || hasMethod(t, name)
+}
+
+// push enters a named type that is about to be inlined, and fails on recursion.
+func (g *Generator) push(t types.Type) (ok bool, err error) {
+	named, ok := t.(*types.Named)
+
+	if !ok {
+		return
+	}
+
+	for _, n := range g.stack {
+		if n == named {
+			return false, fmt.Errorf("recursive type %s must be listed in -type", g.typeString(named))
+		}
+	}
+
+	g.stack = append(g.stack, named)
+	return true, nil
+}
+
+func (g *Generator) pop(ok bool) {
+	if ok {
+		g.stack = g.stack[:len(g.stack)-1]
+	}
+}
+
+type structField struct {
+	name string
+	typ  types.Type
+}
+
+// fields returns the fields that are encoded, the same way as binary.Marshal does.
+func fields(s *types.Struct) (f []structField) {
+	for i := range s.NumFields() {
+		v := s.Field(i)
+
+		if !v.Exported() || reflect.StructTag(s.Tag(i)).Get("bin") == "-" {
+			continue
+		}
+
+		f = append(f, structField{name: v.Name(), typ: v.Type()})
+	}
+
+	return
+}
+
+// opaque reports whether s only has unexported fields, in which case binary.Marshal encodes
+// it through its encoding.BinaryMarshaler and encoding.BinaryUnmarshaler methods.
+func opaque(s *types.Struct) bool {
+	for i := range s.NumFields() {
+		if s.Field(i).Exported() {
+			return false
+		}
+	}
+
+	return s.NumFields() > 0
+}
+
+// timeMethod returns the suffix of the Writer and Reader methods of time.Time and
+// time.Duration, which are encoded by WriteTime and WriteDuration the same way as
+// binary.Marshal does, or "" for any other type.
+func timeMethod(t types.Type) string {
+	named, ok := t.(*types.Named)
+
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "time" {
+		return ""
+	}
+
+	if name := named.Obj().Name(); name == "Time" || name == "Duration" {
+		return name
+	}
+
+	return ""
+}
+
+// binaryMarshaler reports whether t implements both encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler.
+func binaryMarshaler(t types.Type) bool {
+	return hasMethod(t, "MarshalBinary") && hasMethod(t, "UnmarshalBinary")
+}
+
+var basicMethods = map[types.BasicKind]struct {
+	method string
+	typ    string
+}{
+	types.Bool:    {"Bool", "bool"},
+	types.Int:     {"Int", "int"},
+	types.Int8:    {"Int8", "int8"},
+	types.Int16:   {"Int16", "int16"},
+	types.Int32:   {"Int32", "int32"},
+	types.Int64:   {"Int64", "int64"},
+	types.Uint:    {"Uint", "uint"},
+	types.Uint8:   {"Uint8", "uint8"},
+	types.Uint16:  {"Uint16", "uint16"},
+	types.Uint32:  {"Uint32", "uint32"},
+	types.Uint64:  {"Uint64", "uint64"},
+	types.Uintptr: {"Uint64", "uint64"},
+	types.Float32: {"Float32", "float32"},
+	types.Float64: {"Float64", "float64"},
+}
+
+// floatBits returns the size of each float in a complex number.
+func floatBits(k types.BasicKind) int {
+	if k == types.Complex64 {
+		return 32
+	}
+
+	return 64
+}
+
+func isBytes(t *types.Slice) bool {
+	return types.Identical(t.Elem(), types.Typ[types.Uint8])
+}
+
+func (g *Generator) unsupported(t types.Type) error {
+	return fmt.Errorf("unsupported type %s", g.typeString(t))
+}
+
+const checkErr = "; err != nil {\nreturn\n}\n"
+
+// check generates a decode error check of stmt, which returns err wrapped with the field
+// path of the value being decoded.
+func (g *Generator) check(stmt string) {
+	g.printf("if %s; err != nil {\nreturn %s\n}\n", stmt, g.wrap("err"))
+}
+
+// wrap returns the expression of err wrapped with the field path of the value being decoded.
+func (g *Generator) wrap(err string) string {
+	for i := len(g.path) - 1; i >= 0; i-- {
+		err = fmt.Sprintf(g.path[i], err)
+	}
+
+	return err
+}
+
+// guard generates a check that a pointer, slice or map isn't nested deeper than
+// binary.MaxDepth, which returns err.
+func (g *Generator) guard(err string) {
+	g.printf("if depth >= %s.MaxDepth {\nreturn %s\n}\n", g.use(binaryPath, "binary"), err)
+}
+
+// deeper is generated at the start of the block of a pointer, slice or map.
+const deeper = "depth := depth + 1\n_ = depth\n"
+
+// enter adds a wrapper of decode errors, e.g. "binary.FieldError(%s, \"Name\")", to the
+// field path.
+func (g *Generator) enter(format string, args ...any) {
+	g.path = append(g.path, fmt.Sprintf(format, args...))
+}
+
+func (g *Generator) leave() {
+	g.path = g.path[:len(g.path)-1]
+}
+
+func (g *Generator) encode(expr string, t types.Type) (err error) {
+	if m := timeMethod(t); m != "" {
+		g.printf("if err = w.Write%s(%s)"+checkErr, m, expr)
+		return
+	}
+
+	if t != t.Underlying() {
+		if g.generated(t) {
+			g.printf("if err = %s.fastgenEncode(w, depth)"+checkErr, expr)
+			return
+		}
+
+		if g.method(t, "Encode") {
+			g.printf("if err = %s.Encode(w)"+checkErr, expr)
+			return
+		}
+
+		ok, err := g.push(t)
+
+		if err != nil {
+			return err
+		}
+
+		defer g.pop(ok)
+	}
+
+	switch u := t.Underlying().(type) {
+
+	case *types.Basic:
+		switch k := u.Kind(); k {
+		case types.String:
+			g.printf("if err = w.WriteUvarint(uint64(len(%s)))"+checkErr, expr)
+			g.printf("if _, err = w.WriteString(string(%s))"+checkErr, expr)
+		case types.Complex64, types.Complex128:
+			g.printf("if err = w.WriteFloat%d(real(%s))"+checkErr, floatBits(k), expr)
+			g.printf("if err = w.WriteFloat%d(imag(%s))"+checkErr, floatBits(k), expr)
+		default:
+			m, ok := basicMethods[k]
+
+			if !ok {
+				return g.unsupported(t)
+			}
+
+			g.printf("if err = w.Write%s(%s(%s))"+checkErr, m.method, m.typ, expr)
+		}
+
+	case *types.Slice:
+		if !isBytes(u) {
+			g.guard(g.use(binaryPath, "binary") + ".ErrTooDeep")
+		}
+
+		g.printf("if err = w.WriteUvarint(uint64(len(%s)))"+checkErr, expr)
+
+		if isBytes(u) {
+			g.printf("if _, err = w.Write([]byte(%s))"+checkErr, expr)
+			return
+		}
+
+		i := g.newVar("i")
+		g.printf("for %s := range %s {\n", i, expr)
+		g.printf(deeper)
+
+		if err = g.encode(fmt.Sprintf("%s[%s]", expr, i), u.Elem()); err != nil {
+			return
+		}
+
+		g.printf("}\n")
+
+	case *types.Array:
+		i := g.newVar("i")
+		g.printf("for %s := range %s {\n", i, expr)
+
+		if err = g.encode(fmt.Sprintf("%s[%s]", expr, i), u.Elem()); err != nil {
+			return
+		}
+
+		g.printf("}\n")
+
+	case *types.Map:
+		k, v := g.newVar("k"), g.newVar("v")
+		g.guard(g.use(binaryPath, "binary") + ".ErrTooDeep")
+		g.printf("if err = w.WriteUvarint(uint64(len(%s)))"+checkErr, expr)
+		g.printf("for %s, %s := range %s {\n", k, v, expr)
+		g.printf(deeper)
+
+		if err = g.encode(k, u.Key()); err != nil {
+			return
+		}
+
+		if err = g.encode(v, u.Elem()); err != nil {
+			return
+		}
+
+		g.printf("}\n")
+
+	case *types.Pointer:
+		g.printf("if err = w.WriteBool(%s != nil)"+checkErr, expr)
+		g.printf("if %s != nil {\n", expr)
+		g.guard(g.use(binaryPath, "binary") + ".ErrTooDeep")
+		g.printf(deeper)
+
+		if err = g.encode(fmt.Sprintf("(*%s)", expr), u.Elem()); err != nil {
+			return
+		}
+
+		g.printf("}\n")
+
+	case *types.Struct:
+		if opaque(u) {
+			if !binaryMarshaler(t) {
+				return g.unsupported(t)
+			}
+
+			b := g.newVar("b")
+			g.printf("var %s []byte\n", b)
+			g.printf("if %s, err = %s.MarshalBinary()"+checkErr, b, expr)
+			g.printf("if err = w.WriteUvarint(uint64(len(%s)))"+checkErr, b)
+			g.printf("if _, err = w.Write(%s)"+checkErr, b)
+			return
+		}
+
+		for _, f := range fields(u) {
+			if err = g.encode(expr+"."+f.name, f.typ); err != nil {
+				return
+			}
+		}
+
+	default:
+		return g.unsupported(t)
+
+	}
+
+	return
+}
+
+func (g *Generator) decode(expr string, t types.Type) (err error) {
+	if m := timeMethod(t); m != "" {
+		g.printf("%s = r.Read%s()\n", expr, m)
+		return
+	}
+
+	if t != t.Underlying() {
+		if g.generated(t) {
+			g.check(fmt.Sprintf("err = %s.fastgenDecode(r, depth)", expr))
+			return
+		}
+
+		if g.method(t, "Decode") {
+			g.check(fmt.Sprintf("err = %s.Decode(r)", expr))
+			return
+		}
+
+		ok, err := g.push(t)
+
+		if err != nil {
+			return err
+		}
+
+		defer g.pop(ok)
+	}
+
+	binaryPkg := g.use(binaryPath, "binary")
+
+	switch u := t.Underlying().(type) {
+
+	case *types.Basic:
+		switch k := u.Kind(); k {
+		case types.String:
+			// Strings and byte slices are read through binary.ReadLenBytes, which bounds the
+			// allocation by the data actually read.
+			b := g.newVar("b")
+			g.printf("var %s []byte\n", b)
+			g.check(fmt.Sprintf("%s, err = %s.ReadLenBytes(r)", b, binaryPkg))
+			g.printf("%s = %s(%s.BytesToString(%s))\n", expr, g.typeString(t), g.use(fastPath, "fast"), b)
+		case types.Complex64, types.Complex128:
+			g.printf("%s = %s(complex(r.ReadFloat%d(), r.ReadFloat%d()))\n", expr, g.typeString(t), floatBits(k), floatBits(k))
+		default:
+			m, ok := basicMethods[k]
+
+			if !ok {
+				return g.unsupported(t)
+			}
+
+			g.printf("%s = %s(r.Read%s())\n", expr, g.typeString(t), m.method)
+		}
+
+	case *types.Slice:
+		typ := g.typeString(t)
+
+		if isBytes(u) {
+			b := g.newVar("b")
+			g.printf("var %s []byte\n", b)
+			g.check(fmt.Sprintf("%s, err = %s.ReadLenBytes(r)", b, binaryPkg))
+			g.printf("%s = %s(%s)\n", expr, typ, b)
+			return
+		}
+
+		n := g.newVar("n")
+		g.guard(g.wrap(binaryPkg + ".ErrTooDeep"))
+		g.printf("var %s int\n", n)
+		g.check(fmt.Sprintf("%s, err = %s.ReadLen(r)", n, binaryPkg))
+
+		// The slice is grown as elements are decoded, so that a malicious length can't
+		// allocate more memory than the data backing it.
+		e := g.newVar("e")
+		elem := g.typeString(u.Elem())
+		g.printf("if %s == 0 {\n%s = nil\n} else {\n", n, expr)
+		g.printf(deeper)
+		g.printf("%s = make(%s, 0, %s.PreallocLen[%s](%s))\n", expr, typ, binaryPkg, elem, n)
+		g.printf("for range %s {\nvar %s %s\n", n, e, elem)
+		g.enter("%s.IndexError(%%s, len(%s))", binaryPkg, expr)
+
+		if err = g.decode(e, u.Elem()); err != nil {
+			return
+		}
+
+		g.check("err = r.Error()")
+		g.leave()
+		g.printf("%s = append(%s, %s)\n}\n}\n", expr, expr, e)
+
+	case *types.Array:
+		i := g.newVar("i")
+		g.printf("for %s := range %s {\n", i, expr)
+		g.enter("%s.IndexError(%%s, %s)", binaryPkg, i)
+
+		if err = g.decode(fmt.Sprintf("%s[%s]", expr, i), u.Elem()); err != nil {
+			return
+		}
+
+		g.check("err = r.Error()")
+		g.leave()
+		g.printf("}\n")
+
+	case *types.Map:
+		n, k, v := g.newVar("n"), g.newVar("k"), g.newVar("v")
+		g.guard(g.wrap(binaryPkg + ".ErrTooDeep"))
+		g.printf("var %s int\n", n)
+		g.check(fmt.Sprintf("%s, err = %s.ReadLen(r)", n, binaryPkg))
+		g.printf("if %s == 0 {\n%s = nil\n} else {\n", n, expr)
+		g.printf(deeper)
+		g.printf("%s = make(%s, %s.PreallocLen[%s](%s))\n", expr, g.typeString(t), binaryPkg, g.typeString(u.Key()), n)
+		g.printf("for range %s {\n", n)
+		g.printf("var %s %s\nvar %s %s\n", k, g.typeString(u.Key()), v, g.typeString(u.Elem()))
+
+		if err = g.decode(k, u.Key()); err != nil {
+			return
+		}
+
+		g.check("err = r.Error()")
+		g.enter("%s.KeyError(%%s, %s)", binaryPkg, k)
+
+		if err = g.decode(v, u.Elem()); err != nil {
+			return
+		}
+
+		g.check("err = r.Error()")
+		g.leave()
+		g.printf("%s[%s] = %s\n}\n}\n", expr, k, v)
+
+	case *types.Pointer:
+		g.printf("if r.ReadBool() {\n")
+		g.guard(g.wrap(binaryPkg + ".ErrTooDeep"))
+		g.printf(deeper)
+		g.printf("%s = new(%s)\n", expr, g.typeString(u.Elem()))
+
+		if err = g.decode(fmt.Sprintf("(*%s)", expr), u.Elem()); err != nil {
+			return
+		}
+
+		g.printf("} else {\n%s = nil\n}\n", expr)
+
+	case *types.Struct:
+		if opaque(u) {
+			if !binaryMarshaler(t) {
+				return g.unsupported(t)
+			}
+
+			b := g.newVar("b")
+			g.printf("var %s []byte\n", b)
+			g.check(fmt.Sprintf("%s, err = %s.ReadLenBytes(r)", b, binaryPkg))
+			g.check(fmt.Sprintf("err = %s.UnmarshalBinary(%s)", expr, b))
+			return
+		}
+
+		for _, f := range fields(u) {
+			g.enter("%s.FieldError(%%s, %q)", binaryPkg, f.name)
+
+			if err = g.decode(expr+"."+f.name, f.typ); err != nil {
+				return
+			}
+
+			g.check("err = r.Error()")
+			g.leave()
+		}
+
+	default:
+		return g.unsupported(t)
+
+	}
+
+	return
+}
+
+// str generates a textual representation, similar to the %+v verb of fmt. Errors have
+// already been reported by encode.
+func (g *Generator) str(expr string, t types.Type) {
+	if timeMethod(t) != "" {
+		g.printf("b.WriteString(%s.String())\n", expr)
+		return
+	}
+
+	if t != t.Underlying() && g.method(t, "EncodeString") {
+		g.printf("%s.EncodeString(b)\n", expr)
+		return
+	}
+
+	switch u := t.Underlying().(type) {
+
+	case *types.Basic:
+		switch k := u.Kind(); {
+		case k == types.String:
+			g.printf("b.WriteString(string(%s))\n", expr)
+		case k == types.Bool:
+			g.printf("b.WriteBool(bool(%s))\n", expr)
+		case k == types.Complex64 || k == types.Complex128:
+			g.printf("b.WriteString(%s.FormatComplex(complex128(%s), 'g', -1, %d))\n", g.use("strconv", "strconv"), expr, 2*floatBits(k))
+		case u.Info()&types.IsUnsigned != 0:
+			g.printf("b.WriteUint64(uint64(%s))\n", expr)
+		case u.Info()&types.IsInteger != 0:
+			g.printf("b.WriteInt64(int64(%s))\n", expr)
+		case k == types.Float32:
+			g.printf("b.WriteFloat32(float32(%s))\n", expr)
+		case k == types.Float64:
+			g.printf("b.WriteFloat64(float64(%s))\n", expr)
+		}
+
+	case *types.Slice:
+		g.strList(expr, u.Elem())
+
+	case *types.Array:
+		g.strList(expr, u.Elem())
+
+	case *types.Map:
+		k, v := g.newVar("k"), g.newVar("v")
+		first := g.newVar("first")
+		g.printf("b.WriteString(\"map[\")\n%s := true\n", first)
+		g.printf("for %s, %s := range %s {\n", k, v, expr)
+		g.printf("if !%s {\nb.WriteByte(' ')\n}\n%s = false\n", first, first)
+		g.str(k, u.Key())
+		g.printf("b.WriteByte(':')\n")
+		g.str(v, u.Elem())
+		g.printf("}\nb.WriteByte(']')\n")
+
+	case *types.Pointer:
+		g.printf("if %s == nil {\nb.WriteString(\"<nil>\")\n} else {\nb.WriteByte('&')\n", expr)
+		g.str(fmt.Sprintf("(*%s)", expr), u.Elem())
+		g.printf("}\n")
+
+	case *types.Struct:
+		if opaque(u) {
+			if hasMethod(t, "String") {
+				g.printf("b.WriteString(%s.String())\n", expr)
+			}
+
+			return
+		}
+
+		g.printf("b.WriteByte('{')\n")
+
+		for i, f := range fields(u) {
+			if i > 0 {
+				g.printf("b.WriteString(\" %s:\")\n", f.name)
+			} else {
+				g.printf("b.WriteString(\"%s:\")\n", f.name)
+			}
+
+			g.str(expr+"."+f.name, f.typ)
+		}
+
+		g.printf("b.WriteByte('}')\n")
+
+	}
+}
+
+func (g *Generator) strList(expr string, elem types.Type) {
+	i := g.newVar("i")
+	g.printf("b.WriteByte('[')\n")
+	g.printf("for %s := range %s {\n", i, expr)
+	g.printf("if %s > 0 {\nb.WriteByte(' ')\n}\n", i)
+	g.str(fmt.Sprintf("%s[%s]", expr, i), elem)
+	g.printf("}\nb.WriteByte(']')\n")
+}
+
+// fill generates code that fills a value with deterministic, non-zero sample values.
+func (g *Generator) fill(expr string, t types.Type) {
+	// Times are decoded in UTC, unless they have a zone offset.
+	if timeMethod(t) == "Time" {
+		g.leaves++
+		g.printf("%s = %s.Unix(%d, %d).UTC()\n", expr, g.use("time", "time"), 1700000000+g.leaves, g.leaves)
+		return
+	}
+
+	if named, ok := t.(*types.Named); ok {
+		if g.requested[named] {
+			g.printf("fastgenFill%s(&%s, depth)\n", named.Obj().Name(), expr)
+			return
+		}
+
+		// Types with their own methods might have invariants that we don't know about.
+		if hasMethod(t, "Encode") || hasMethod(t, "Decode") {
+			return
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+
+	case *types.Basic:
+		typ := g.typeString(t)
+		g.leaves++
+		n := g.leaves
+
+		switch k := u.Kind(); {
+		case k == types.String:
+			g.printf("%s = %s(\"s%d\")\n", expr, typ, n)
+		case k == types.Bool:
+			g.printf("%s = %s(%t)\n", expr, typ, n%2 == 1)
+		case k == types.Complex64 || k == types.Complex128:
+			g.printf("%s = %s(complex(%d, -%d))\n", expr, typ, n, n)
+		case u.Info()&types.IsFloat != 0:
+			g.printf("%s = %s(%d.5)\n", expr, typ, n)
+		case u.Info()&types.IsInteger != 0:
+			// Keep within the range of the smallest integers.
+			g.printf("%s = %s(%d)\n", expr, typ, n%100+1)
+		}
+
+	case *types.Slice:
+		g.printf("if depth < %d {\n%s = make(%s, 2)\n", fillDepth, expr, g.typeString(t))
+		g.printf(deeper)
+		g.fill(expr+"[0]", u.Elem())
+		g.fill(expr+"[1]", u.Elem())
+		g.printf("}\n")
+
+	case *types.Array:
+		i := g.newVar("i")
+		g.printf("for %s := range %s {\n", i, expr)
+		g.fill(fmt.Sprintf("%s[%s]", expr, i), u.Elem())
+		g.printf("}\n")
+
+	case *types.Map:
+		g.printf("if depth < %d {\n%s = make(%s, 2)\n", fillDepth, expr, g.typeString(t))
+		g.printf(deeper)
+
+		for range 2 {
+			k, v := g.newVar("k"), g.newVar("v")
+			g.printf("var %s %s\nvar %s %s\n", k, g.typeString(u.Key()), v, g.typeString(u.Elem()))
+			g.fill(k, u.Key())
+			g.fill(v, u.Elem())
+			g.printf("%s[%s] = %s\n", expr, k, v)
+		}
+
+		g.printf("}\n")
+
+	case *types.Pointer:
+		g.printf("if depth < %d {\n%s = new(%s)\n", fillDepth, expr, g.typeString(u.Elem()))
+		g.printf(deeper)
+		g.fill(fmt.Sprintf("(*%s)", expr), u.Elem())
+		g.printf("}\n")
+
+	// Opaque structs are left zero, as their fields can't be set.
+	case *types.Struct:
+		for _, f := range fields(u) {
+			g.fill(expr+"."+f.name, f.typ)
+		}
+
+	}
+}
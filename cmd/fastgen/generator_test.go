@@ -0,0 +1,210 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func checkSource(t *testing.T, src string) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "types.go", src, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := new(types.Config).Check("example", fset, []*ast.File{f}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pkg
+}
+
+func TestGenerator(t *testing.T) {
+	pkg := checkSource(t, `package example
+
+type Node struct {
+	Name     string
+	Children []*Node
+	Attrs    map[string][]byte
+	skipped  int
+}
+`)
+
+	g, err := NewGenerator(pkg, []string{"Node"}, true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := g.Methods()
+
+	if err != nil {
+		t.Fatalf("%v\n%s", err, src)
+	}
+
+	for _, sig := range []string{
+		"func (v *Node) Encode(w binary.Writer) (err error)",
+		"func (v *Node) Decode(r binary.Reader) (err error)",
+		"func (v *Node) EncodeString(b *fast.StringBuffer)",
+		"(*v.Children[i1]).fastgenEncode(w, depth)",
+		`binary.FieldError(err, "Children")`,
+	} {
+		if !strings.Contains(string(src), sig) {
+			t.Errorf("expected generated code to contain %q:\n%s", sig, src)
+		}
+	}
+
+	if strings.Contains(string(src), "skipped") {
+		t.Errorf("expected unexported fields to be skipped:\n%s", src)
+	}
+
+	if src, err = g.Tests(); err != nil {
+		t.Fatalf("%v\n%s", err, src)
+	}
+}
+
+func TestGenerator_Recursive(t *testing.T) {
+	pkg := checkSource(t, `package example
+
+type List struct {
+	Head *Elem
+}
+
+type Elem struct {
+	Value int
+	Next  *Elem
+}
+`)
+
+	g, err := NewGenerator(pkg, []string{"List"}, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = g.Methods(); err == nil || !strings.Contains(err.Error(), "Elem must be listed") {
+		t.Errorf("expected a recursion error, got %v", err)
+	}
+
+	if g, err = NewGenerator(pkg, []string{"List", "Elem"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := g.Methods()
+
+	if err != nil {
+		t.Fatalf("%v\n%s", err, src)
+	}
+
+	// Decoding is limited to the same depth as binary.Unmarshal, which is tested by the
+	// golden test.
+	for _, sig := range []string{
+		"if depth >= binary.MaxDepth {",
+		`return binary.FieldError(binary.ErrTooDeep, "Next")`,
+		"(*v.Next).fastgenDecode(r, depth)",
+	} {
+		if !strings.Contains(string(src), sig) {
+			t.Errorf("expected generated code to contain %q:\n%s", sig, src)
+		}
+	}
+}
+
+// TestGenerator_Golden generates methods for the golden package, and makes sure that the
+// result builds, passes vet and round-trips through its generated tests.
+func TestGenerator_Golden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping build of generated code in short mode")
+	}
+
+	goBin, err := exec.LookPath("go")
+
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	// The copy must be inside the module to resolve its imports, and inside testdata so that
+	// it's ignored by ./... patterns.
+	dir, err := os.MkdirTemp("testdata", "build-")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	files, err := filepath.Glob(filepath.Join("testdata", "golden", "*.go"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = os.WriteFile(filepath.Join(dir, filepath.Base(file)), src, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pkg, err := loadPackage(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewGenerator(pkg, []string{"Order", "Item", "Elem"}, true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	methods, err := g.Methods()
+
+	if err != nil {
+		t.Fatalf("%v\n%s", err, methods)
+	}
+
+	for _, sig := range []string{"w.WriteTime(v.Created)", "v.TTL = r.ReadDuration()"} {
+		if !strings.Contains(string(methods), sig) {
+			t.Errorf("expected generated code to contain %q", sig)
+		}
+	}
+
+	tests, err := g.Tests()
+
+	if err != nil {
+		t.Fatalf("%v\n%s", err, tests)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, "order_fast.go"), methods, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, "order_fast_test.go"), tests, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"vet"}, {"test", "-count=1"}} {
+		cmd := exec.Command(goBin, append(args, "./"+filepath.ToSlash(dir))...)
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go %s: %v\n%s\n%s", args[0], err, out, methods)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Fastgen generates reflection-free binary and string encoding methods for struct types.
+//
+// For each listed type T, it generates:
+//
+//	func (v *T) Encode(w binary.Writer) error
+//	func (v *T) Decode(r binary.Reader) error
+//	func (v *T) EncodeString(b *fast.StringBuffer)
+//
+// The binary encoding is identical to binary.Marshal, so generated and reflected types can be
+// mixed freely, and nesting is limited to binary.MaxDepth the same way. A round-trip test is
+// generated alongside the methods. Typical usage:
+//
+//	//go:generate go run github.com/webmafia/fast/cmd/fastgen -type Order,Item
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of type names; must be set")
+	output    = flag.String("output", "", "output file name; default <type>_fast.go")
+	genTest   = flag.Bool("test", true, "generate a round-trip test in <output>_test.go")
+	genString = flag.Bool("string", true, "generate EncodeString methods")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of fastgen:\n")
+	fmt.Fprintf(os.Stderr, "\tfastgen -type T,U [flags] [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("fastgen: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	pkg, err := loadPackage(dir)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	names := strings.Split(*typeNames, ",")
+	g, err := NewGenerator(pkg, names, *genString)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out := *output
+
+	if out == "" {
+		out = strings.ToLower(names[0]) + "_fast.go"
+	}
+
+	out = filepath.Join(dir, out)
+
+	src, err := g.Methods()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = os.WriteFile(out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	if !*genTest {
+		return
+	}
+
+	if src, err = g.Tests(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err = os.WriteFile(strings.TrimSuffix(out, ".go")+"_test.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadPackage parses and type-checks the package in dir. Type errors are ignored, as the
+// package might refer to methods that haven't been generated yet.
+func loadPackage(dir string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, f)
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+
+	pkg, _ := conf.Check(bp.ImportPath, fset, files, nil)
+	return pkg, nil
+}
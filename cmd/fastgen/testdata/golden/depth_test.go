@@ -0,0 +1,57 @@
+package golden
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/webmafia/fast/binary"
+)
+
+func TestElem_Depth(t *testing.T) {
+	// Every pair of 0x01 is a value and a present pointer, which used to recurse until the
+	// stack overflowed.
+	r := binary.NewBufferReader(bytes.Repeat([]byte{1}, 8<<20))
+
+	if err := new(Elem).Decode(r); !errors.Is(err, binary.ErrTooDeep) {
+		t.Errorf("expected ErrTooDeep, got %v", err)
+	}
+
+	cyclic := &Elem{}
+	cyclic.Next = cyclic
+
+	if err := cyclic.Encode(binary.NewBufferWriter(64)); !errors.Is(err, binary.ErrTooDeep) {
+		t.Errorf("expected ErrTooDeep for a cyclic value, got %v", err)
+	}
+
+	// The maximum depth itself round trips, and is compatible with binary.Marshal.
+	var src Elem
+
+	for i := range binary.MaxDepth {
+		next := src
+		src = Elem{Value: i, Next: &next}
+	}
+
+	w := binary.NewBufferWriter(64)
+
+	if err := src.Encode(w); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst Elem
+
+	if err := dst.Decode(binary.NewBufferReader(w.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(dst, src) {
+		t.Error("expected the nested elements to round trip")
+	}
+
+	type plain Elem
+
+	if err := binary.Marshal(binary.NewBufferWriter(64), (*plain)(&src)); err != nil {
+		t.Errorf("expected binary.Marshal to accept the same depth, got %v", err)
+	}
+}
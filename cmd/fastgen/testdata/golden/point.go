@@ -0,0 +1,26 @@
+package golden
+
+import (
+	"github.com/webmafia/fast"
+	"github.com/webmafia/fast/binary"
+)
+
+func (p *Point) Encode(w binary.Writer) (err error) {
+	if err = w.WriteInt16(p.X); err != nil {
+		return
+	}
+
+	return w.WriteInt16(p.Y)
+}
+
+func (p *Point) Decode(r binary.Reader) error {
+	p.X = r.ReadInt16()
+	p.Y = r.ReadInt16()
+	return r.Error()
+}
+
+func (p *Point) EncodeString(b *fast.StringBuffer) {
+	b.WriteInt(int(p.X))
+	b.WriteByte(',')
+	b.WriteInt(int(p.Y))
+}
@@ -0,0 +1,50 @@
+// Package golden holds types covering everything fastgen supports. The generator tests
+// generate methods for them, and build, vet and run the result.
+package golden
+
+import (
+	"net/netip"
+	"time"
+)
+
+type Status uint8
+
+type Name string
+
+type Blob []byte
+
+type Order struct {
+	ID       uint64
+	Status   Status
+	Customer *Name
+	Items    []Item
+	Counts   map[string]int32
+	Hash     [4]byte
+	Raw      []byte
+	Blob     Blob
+	Addr     netip.Addr
+	Created  time.Time
+	Ratio    complex128
+	Next     *Order
+	Ignored  int `bin:"-"`
+	internal int
+}
+
+type Item struct {
+	Name  Name
+	Price float64
+	TTL   time.Duration
+	Tags  []string
+	Point Point
+}
+
+// Point has its own methods, which are used instead of inlining it.
+type Point struct {
+	X, Y int16
+}
+
+// Elem is a recursive type, which must be decoded with a limited depth.
+type Elem struct {
+	Value int
+	Next  *Elem
+}
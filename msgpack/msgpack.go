@@ -0,0 +1,103 @@
+// Package msgpack implements the MessagePack format (https://msgpack.org) on top of
+// binary.Writer and binary.Reader. Writers always pick the smallest representation of a
+// value, and readers decode one token at a time without allocating.
+package msgpack
+
+import "errors"
+
+var (
+	ErrInvalidType = errors.New("invalid msgpack type")
+	ErrTooLarge    = errors.New("length too large")
+)
+
+// Format bytes, as defined by the specification.
+const (
+	posFixintMax = 0x7f
+	fixmap       = 0x80
+	fixarray     = 0x90
+	fixstr       = 0xa0
+	nilByte      = 0xc0
+	neverUsed    = 0xc1
+	falseByte    = 0xc2
+	trueByte     = 0xc3
+	bin8         = 0xc4
+	bin16        = 0xc5
+	bin32        = 0xc6
+	ext8         = 0xc7
+	ext16        = 0xc8
+	ext32        = 0xc9
+	float32Byte  = 0xca
+	float64Byte  = 0xcb
+	uint8Byte    = 0xcc
+	uint16Byte   = 0xcd
+	uint32Byte   = 0xce
+	uint64Byte   = 0xcf
+	int8Byte     = 0xd0
+	int16Byte    = 0xd1
+	int32Byte    = 0xd2
+	int64Byte    = 0xd3
+	fixext1      = 0xd4
+	fixext2      = 0xd5
+	fixext4      = 0xd6
+	fixext8      = 0xd7
+	fixext16     = 0xd8
+	str8         = 0xd9
+	str16        = 0xda
+	str32        = 0xdb
+	array16      = 0xdc
+	array32      = 0xdd
+	map16        = 0xde
+	map32        = 0xdf
+	negFixintMin = 0xe0
+)
+
+// TimestampExt is the extension type of timestamps.
+const TimestampExt int8 = -1
+
+const timestampExtByte = 0xff
+
+// Kind is the kind of a Token.
+type Kind uint8
+
+const (
+	Nil Kind = iota
+	Bool
+	Int
+	Uint
+	Float32
+	Float64
+	String
+	Bytes
+	Array
+	Map
+	Ext
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Nil:
+		return "nil"
+	case Bool:
+		return "bool"
+	case Int:
+		return "int"
+	case Uint:
+		return "uint"
+	case Float32:
+		return "float32"
+	case Float64:
+		return "float64"
+	case String:
+		return "string"
+	case Bytes:
+		return "bytes"
+	case Array:
+		return "array"
+	case Map:
+		return "map"
+	case Ext:
+		return "ext"
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,249 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/webmafia/fast/binary"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		val      any
+		expected string
+	}{
+		{nil, "c0"},
+		{true, "c3"},
+		{false, "c2"},
+		{0, "00"},
+		{127, "7f"},
+		{128, "cc80"},
+		{256, "cd0100"},
+		{1 << 16, "ce00010000"},
+		{uint64(1 << 32), "cf0000000100000000"},
+		{-1, "ff"},
+		{-32, "e0"},
+		{-33, "d0df"},
+		{-129, "d1ff7f"},
+		{-32769, "d2ffff7fff"},
+		{int64(math.MinInt64), "d38000000000000000"},
+		{1.5, "ca3fc00000"},
+		{0.1, "cb3fb999999999999a"},
+		{"", "a0"},
+		{"foo", "a3666f6f"},
+		{strings.Repeat("x", 32), "d920" + strings.Repeat("78", 32)},
+		{[]byte{1, 2}, "c4020102"},
+		{[]any{1, "a"}, "9201a161"},
+		{map[string]any{"a": nil}, "81a161c0"},
+		{time.Unix(1, 0), "d6ff00000001"},
+		{time.Unix(1, 1), "d7ff0000000400000001"},
+		{time.Unix(-1, 0), "c70cff00000000ffffffffffffffff"},
+	}
+
+	for _, tt := range tests {
+		w := binary.NewBufferWriter(64)
+
+		if err := NewWriter(w).WriteVal(tt.val); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := hex.EncodeToString(w.Bytes()); got != tt.expected {
+			t.Errorf("%v: expected %s, got %s", tt.val, tt.expected, got)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	now := time.Now().UTC().Round(0)
+	w := binary.NewBufferWriter(64)
+	mw := NewWriter(w)
+	mw.WriteMapHeader(2)
+	mw.WriteString("ints")
+	mw.WriteArrayHeader(3)
+	mw.WriteInt(-1000)
+	mw.WriteUint(math.MaxUint64)
+	mw.WriteInt(7)
+	mw.WriteString("rest")
+	mw.WriteArrayHeader(5)
+	mw.WriteFloat64(0.1)
+	mw.WriteBytes(bytes.Repeat([]byte{1}, 300))
+	mw.WriteTime(now)
+	mw.WriteExt(42, []byte{1, 2, 3})
+	mw.WriteBool(true)
+
+	for _, br := range []binary.Reader{
+		binary.NewBufferReader(w.Bytes()),
+		binary.NewStreamReader(bytes.NewReader(w.Bytes())),
+	} {
+		r := NewReader(br)
+
+		expectNoErr := func(err error) {
+			t.Helper()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		n, err := r.ReadMapHeader()
+		expectNoErr(err)
+
+		if n != 2 {
+			t.Fatalf("expected 2 pairs, got %d", n)
+		}
+
+		key, err := r.ReadString()
+		expectNoErr(err)
+
+		if key != "ints" {
+			t.Errorf("expected 'ints', got '%s'", key)
+		}
+
+		// Skip the array of ints.
+		expectNoErr(r.Skip())
+
+		key, err = r.ReadString()
+		expectNoErr(err)
+
+		if key != "rest" {
+			t.Errorf("expected 'rest', got '%s'", key)
+		}
+
+		n, err = r.ReadArrayHeader()
+		expectNoErr(err)
+
+		if n != 5 {
+			t.Fatalf("expected 5 elements, got %d", n)
+		}
+
+		f, err := r.ReadFloat()
+		expectNoErr(err)
+
+		if f != 0.1 {
+			t.Errorf("expected 0.1, got %v", f)
+		}
+
+		b, err := r.ReadBytes()
+		expectNoErr(err)
+
+		if len(b) != 300 {
+			t.Errorf("expected 300 bytes, got %d", len(b))
+		}
+
+		tm, err := r.ReadTime()
+		expectNoErr(err)
+
+		if !tm.Equal(now) {
+			t.Errorf("expected %v, got %v", now, tm)
+		}
+
+		tok, err := r.Next()
+		expectNoErr(err)
+
+		if tok.Kind != Ext || tok.Ext != 42 || !bytes.Equal(tok.Bytes, []byte{1, 2, 3}) {
+			t.Errorf("unexpected token %+v", tok)
+		}
+
+		if _, err = r.ReadInt(); !errors.Is(err, ErrInvalidType) {
+			t.Errorf("expected ErrInvalidType, got %v", err)
+		}
+
+		if _, err = r.Next(); err != io.EOF {
+			t.Errorf("expected io.EOF, got %v", err)
+		}
+
+		r.Release()
+	}
+}
+
+func TestReader_Ints(t *testing.T) {
+	values := []int64{0, 1, -1, 127, 128, -32, -33, math.MaxInt8, math.MinInt8, math.MaxInt16, math.MinInt16, math.MaxInt32, math.MinInt32, math.MaxInt64, math.MinInt64}
+	w := binary.NewBufferWriter(64)
+	mw := NewWriter(w)
+
+	for _, v := range values {
+		mw.WriteInt(v)
+	}
+
+	r := NewReader(binary.NewBufferReader(w.Bytes()))
+
+	for _, v := range values {
+		got, err := r.ReadInt()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != v {
+			t.Errorf("expected %d, got %d", v, got)
+		}
+	}
+}
+
+func TestReader_Truncated(t *testing.T) {
+	w := binary.NewBufferWriter(64)
+	NewWriter(w).WriteVal([]any{"foobar", uint64(1 << 40)})
+	data := w.Bytes()
+
+	for i := 1; i < len(data); i++ {
+		r := NewReader(binary.NewBufferReader(data[:i]))
+
+		if err := r.Skip(); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%d bytes: expected io.ErrUnexpectedEOF, got %v", i, err)
+		}
+	}
+
+	// A huge length must not allocate more than there is data.
+	r := NewReader(binary.NewBufferReader([]byte{bin32, 0xff, 0xff, 0xff, 0xff, 1}))
+
+	if _, err := r.ReadBytes(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	if r.scratch.Cap() > 2*maxChunk {
+		t.Errorf("expected at most %d bytes of scratch space, got %d", 2*maxChunk, r.scratch.Cap())
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	w := binary.NewBufferWriter(64)
+	mw := NewWriter(w)
+
+	for i := 0; i < b.N; i++ {
+		w.Reset()
+		mw.WriteMapHeader(2)
+		mw.WriteString("id")
+		mw.WriteInt(int64(i))
+		mw.WriteString("name")
+		mw.WriteString("foobar")
+	}
+}
+
+func BenchmarkReader(b *testing.B) {
+	w := binary.NewBufferWriter(64)
+	mw := NewWriter(w)
+	mw.WriteMapHeader(2)
+	mw.WriteString("id")
+	mw.WriteInt(123456)
+	mw.WriteString("name")
+	mw.WriteString("foobar")
+
+	br := binary.NewBufferReader(w.Bytes())
+	r := NewReader(br)
+	defer r.Release()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		br.Reset()
+
+		if err := r.Skip(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
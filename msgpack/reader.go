@@ -0,0 +1,385 @@
+package msgpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	fastbinary "github.com/webmafia/fast/binary"
+	"github.com/webmafia/fast/buffer"
+)
+
+// Payloads are read in chunks of at most this size, so that a malicious length can't
+// allocate more memory than there is data.
+const maxChunk = 64 << 10
+
+var scratchPool buffer.Pool
+
+// A Token is a single MessagePack value. Arrays and maps are read as a header holding the
+// number of elements or key-value pairs, which are then read as separate tokens.
+type Token struct {
+	Kind  Kind
+	Bool  bool
+	Int   int64
+	Uint  uint64
+	Float float64
+	Len   int    // Number of elements of an array, or key-value pairs of a map
+	Ext   int8   // Extension type
+	Bytes []byte // Contents of a string, bytes or extension; only valid until the next read
+}
+
+// A Reader reads MessagePack tokens from a binary.Reader. Strings, bytes and extensions are
+// read into a scratch buffer from a buffer.Pool, which is returned by Release.
+type Reader struct {
+	r       fastbinary.Reader
+	scratch *buffer.Buffer
+	tmp     [8]byte
+}
+
+// NewReader creates a Reader reading from r.
+func NewReader(r fastbinary.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Reset resets the Reader to read from r.
+func (r *Reader) Reset(br fastbinary.Reader) {
+	r.r = br
+}
+
+// Release returns the scratch buffer to its pool. Byte slices returned by the Reader must
+// not be used after this. The Reader can still be used, and will get a new scratch buffer
+// when needed.
+func (r *Reader) Release() {
+	if r.scratch != nil {
+		scratchPool.Put(r.scratch)
+		r.scratch = nil
+	}
+}
+
+// Next reads the next token. It returns io.EOF if there are no more tokens, and
+// io.ErrUnexpectedEOF if the input ends in the middle of one.
+func (r *Reader) Next() (t Token, err error) {
+	c, err := r.r.ReadByte()
+
+	if err != nil {
+		return
+	}
+
+	switch {
+	case c <= posFixintMax:
+		t.Kind, t.Uint = Uint, uint64(c)
+		return
+	case c >= negFixintMin:
+		t.Kind, t.Int = Int, int64(int8(c))
+		return
+	case c&0xf0 == fixmap:
+		t.Kind, t.Len = Map, int(c&0x0f)
+		return
+	case c&0xf0 == fixarray:
+		t.Kind, t.Len = Array, int(c&0x0f)
+		return
+	case c&0xe0 == fixstr:
+		t.Kind, t.Len = String, int(c&0x1f)
+		t.Bytes, err = r.payload(t.Len)
+		return
+	}
+
+	switch c {
+
+	case nilByte:
+		t.Kind = Nil
+
+	case falseByte, trueByte:
+		t.Kind, t.Bool = Bool, c == trueByte
+
+	case uint8Byte, uint16Byte, uint32Byte, uint64Byte:
+		t.Kind = Uint
+		t.Uint, err = r.uint(1 << (c - uint8Byte))
+
+	case int8Byte, int16Byte, int32Byte, int64Byte:
+		var v uint64
+		size := 1 << (c - int8Byte)
+		v, err = r.uint(size)
+		shift := 64 - 8*size
+		t.Kind, t.Int = Int, int64(v<<shift)>>shift
+
+	case float32Byte:
+		var v uint64
+		v, err = r.uint(4)
+		t.Kind, t.Float = Float32, float64(math.Float32frombits(uint32(v)))
+
+	case float64Byte:
+		var v uint64
+		v, err = r.uint(8)
+		t.Kind, t.Float = Float64, math.Float64frombits(v)
+
+	case str8, str16, str32:
+		t.Kind = String
+		err = r.sized(&t, 1<<(c-str8))
+
+	case bin8, bin16, bin32:
+		t.Kind = Bytes
+		err = r.sized(&t, 1<<(c-bin8))
+
+	case array16, array32:
+		t.Kind = Array
+		t.Len, err = r.len(2 << (c - array16))
+
+	case map16, map32:
+		t.Kind = Map
+		t.Len, err = r.len(2 << (c - map16))
+
+	case fixext1, fixext2, fixext4, fixext8, fixext16:
+		t.Kind, t.Len = Ext, 1<<(c-fixext1)
+		err = r.ext(&t)
+
+	case ext8, ext16, ext32:
+		t.Kind = Ext
+
+		if t.Len, err = r.len(1 << (c - ext8)); err == nil {
+			err = r.ext(&t)
+		}
+
+	default:
+		err = fmt.Errorf("%w: 0x%02x", ErrInvalidType, c)
+
+	}
+
+	return
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (r *Reader) uint(size int) (v uint64, err error) {
+	if _, err = io.ReadFull(r.r, r.tmp[:size]); err != nil {
+		return 0, unexpected(err)
+	}
+
+	switch size {
+	case 1:
+		v = uint64(r.tmp[0])
+	case 2:
+		v = uint64(binary.BigEndian.Uint16(r.tmp[:]))
+	case 4:
+		v = uint64(binary.BigEndian.Uint32(r.tmp[:]))
+	case 8:
+		v = binary.BigEndian.Uint64(r.tmp[:])
+	}
+
+	return
+}
+
+func (r *Reader) len(size int) (n int, err error) {
+	v, err := r.uint(size)
+
+	if err == nil && v > math.MaxInt {
+		err = fmt.Errorf("%w: %d", ErrTooLarge, v)
+	}
+
+	return int(v), err
+}
+
+func (r *Reader) sized(t *Token, size int) (err error) {
+	if t.Len, err = r.len(size); err != nil {
+		return
+	}
+
+	t.Bytes, err = r.payload(t.Len)
+	return
+}
+
+func (r *Reader) ext(t *Token) (err error) {
+	typ, err := r.uint(1)
+
+	if err != nil {
+		return
+	}
+
+	t.Ext = int8(typ)
+	t.Bytes, err = r.payload(t.Len)
+	return
+}
+
+// payload reads n bytes into the scratch buffer, which is grown as data arrives.
+func (r *Reader) payload(n int) ([]byte, error) {
+	if r.scratch == nil {
+		r.scratch = scratchPool.Get()
+	}
+
+	b := r.scratch
+	b.Reset()
+
+	for b.Len() < n {
+		start := b.Len()
+		chunk := min(n-start, maxChunk)
+		b.Grow(chunk)
+		b.B = b.B[:start+chunk]
+
+		if _, err := io.ReadFull(r.r, b.B[start:]); err != nil {
+			b.B = b.B[:start]
+			return nil, unexpected(err)
+		}
+	}
+
+	return b.B, nil
+}
+
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+func (r *Reader) expect(kind Kind) (t Token, err error) {
+	if t, err = r.Next(); err == nil && t.Kind != kind {
+		err = mismatch(kind, t)
+	}
+
+	return
+}
+
+func mismatch(expected Kind, t Token) error {
+	return fmt.Errorf("%w: expected %s, got %s", ErrInvalidType, expected, t.Kind)
+}
+
+func (r *Reader) ReadNil() (err error) {
+	_, err = r.expect(Nil)
+	return
+}
+
+func (r *Reader) ReadBool() (bool, error) {
+	t, err := r.expect(Bool)
+	return t.Bool, err
+}
+
+// ReadInt reads a signed or unsigned integer that fits in an int64.
+func (r *Reader) ReadInt() (v int64, err error) {
+	t, err := r.Next()
+
+	if err != nil {
+		return
+	}
+
+	switch t.Kind {
+	case Int:
+		return t.Int, nil
+	case Uint:
+		if t.Uint > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %d overflows int64", ErrTooLarge, t.Uint)
+		}
+
+		return int64(t.Uint), nil
+	}
+
+	return 0, mismatch(Int, t)
+}
+
+// ReadUint reads a non-negative signed or unsigned integer.
+func (r *Reader) ReadUint() (v uint64, err error) {
+	t, err := r.Next()
+
+	if err != nil {
+		return
+	}
+
+	switch t.Kind {
+	case Uint:
+		return t.Uint, nil
+	case Int:
+		if t.Int >= 0 {
+			return uint64(t.Int), nil
+		}
+	}
+
+	return 0, mismatch(Uint, t)
+}
+
+// ReadFloat reads a float 32 or float 64.
+func (r *Reader) ReadFloat() (v float64, err error) {
+	t, err := r.Next()
+
+	if err == nil && t.Kind != Float32 && t.Kind != Float64 {
+		err = mismatch(Float64, t)
+	}
+
+	return t.Float, err
+}
+
+// ReadString reads a string into newly allocated memory.
+func (r *Reader) ReadString() (string, error) {
+	t, err := r.expect(String)
+	return string(t.Bytes), err
+}
+
+// ReadBytes reads a byte slice, which is only valid until the next read.
+func (r *Reader) ReadBytes() ([]byte, error) {
+	t, err := r.expect(Bytes)
+	return t.Bytes, err
+}
+
+// ReadArrayHeader reads the header of an array and returns its number of elements.
+func (r *Reader) ReadArrayHeader() (int, error) {
+	t, err := r.expect(Array)
+	return t.Len, err
+}
+
+// ReadMapHeader reads the header of a map and returns its number of key-value pairs.
+func (r *Reader) ReadMapHeader() (int, error) {
+	t, err := r.expect(Map)
+	return t.Len, err
+}
+
+// ReadTime reads a timestamp extension in any of the 32, 64 or 96 bit formats. The time is
+// returned in UTC.
+func (r *Reader) ReadTime() (tm time.Time, err error) {
+	t, err := r.expect(Ext)
+
+	if err != nil {
+		return
+	}
+
+	if t.Ext != TimestampExt {
+		return tm, fmt.Errorf("%w: expected timestamp, got extension %d", ErrInvalidType, t.Ext)
+	}
+
+	var sec, nsec int64
+
+	switch len(t.Bytes) {
+	case 4:
+		sec = int64(binary.BigEndian.Uint32(t.Bytes))
+	case 8:
+		v := binary.BigEndian.Uint64(t.Bytes)
+		sec, nsec = int64(v&(1<<34-1)), int64(v>>34)
+	case 12:
+		nsec = int64(binary.BigEndian.Uint32(t.Bytes))
+		sec = int64(binary.BigEndian.Uint64(t.Bytes[4:]))
+	default:
+		return tm, fmt.Errorf("%w: timestamp of %d bytes", ErrInvalidType, len(t.Bytes))
+	}
+
+	return time.Unix(sec, nsec).UTC(), nil
+}
+
+// Skip skips the next value, including all elements of an array or map. It returns
+// io.ErrUnexpectedEOF if the input ends before the value does.
+func (r *Reader) Skip() error {
+	for pending := 1; pending > 0; pending-- {
+		t, err := r.Next()
+
+		if err != nil {
+			return unexpected(err)
+		}
+
+		switch t.Kind {
+		case Array:
+			pending += t.Len
+		case Map:
+			pending += 2 * t.Len
+		}
+	}
+
+	return nil
+}
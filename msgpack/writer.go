@@ -0,0 +1,298 @@
+package msgpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	fastbinary "github.com/webmafia/fast/binary"
+)
+
+// A Writer writes MessagePack values to a binary.Writer, always picking the smallest
+// representation. MessagePack is big-endian regardless of the byte order of the underlying
+// writer.
+type Writer struct {
+	w   fastbinary.Writer
+	tmp [15]byte
+}
+
+// NewWriter creates a Writer writing to w.
+func NewWriter(w fastbinary.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Reset resets the Writer to write to w.
+func (w *Writer) Reset(bw fastbinary.Writer) {
+	w.w = bw
+}
+
+func (w *Writer) write(p []byte) (err error) {
+	_, err = w.w.Write(p)
+	return
+}
+
+// head writes a format byte followed by n as a big-endian integer of size bytes.
+func (w *Writer) head(format byte, n uint64, size int) error {
+	w.tmp[0] = format
+
+	switch size {
+	case 1:
+		w.tmp[1] = byte(n)
+	case 2:
+		binary.BigEndian.PutUint16(w.tmp[1:], uint16(n))
+	case 4:
+		binary.BigEndian.PutUint32(w.tmp[1:], uint32(n))
+	case 8:
+		binary.BigEndian.PutUint64(w.tmp[1:], n)
+	}
+
+	return w.write(w.tmp[:1+size])
+}
+
+func (w *Writer) WriteNil() error {
+	return w.w.WriteUint8(nilByte)
+}
+
+func (w *Writer) WriteBool(v bool) error {
+	if v {
+		return w.w.WriteUint8(trueByte)
+	}
+
+	return w.w.WriteUint8(falseByte)
+}
+
+// WriteInt writes v as a fixint or the smallest integer that fits. Non-negative values
+// are written as unsigned integers.
+func (w *Writer) WriteInt(v int64) error {
+	switch {
+	case v >= 0:
+		return w.WriteUint(uint64(v))
+	case v >= -32:
+		return w.w.WriteUint8(uint8(v))
+	case v >= math.MinInt8:
+		return w.head(int8Byte, uint64(v), 1)
+	case v >= math.MinInt16:
+		return w.head(int16Byte, uint64(v), 2)
+	case v >= math.MinInt32:
+		return w.head(int32Byte, uint64(v), 4)
+	}
+
+	return w.head(int64Byte, uint64(v), 8)
+}
+
+// WriteUint writes v as a fixint or the smallest unsigned integer that fits.
+func (w *Writer) WriteUint(v uint64) error {
+	switch {
+	case v <= posFixintMax:
+		return w.w.WriteUint8(uint8(v))
+	case v <= math.MaxUint8:
+		return w.head(uint8Byte, v, 1)
+	case v <= math.MaxUint16:
+		return w.head(uint16Byte, v, 2)
+	case v <= math.MaxUint32:
+		return w.head(uint32Byte, v, 4)
+	}
+
+	return w.head(uint64Byte, v, 8)
+}
+
+func (w *Writer) WriteFloat32(v float32) error {
+	return w.head(float32Byte, uint64(math.Float32bits(v)), 4)
+}
+
+// WriteFloat64 writes v as a float 32 if it can be represented without loss, and
+// otherwise as a float 64.
+func (w *Writer) WriteFloat64(v float64) error {
+	if f := float32(v); float64(f) == v {
+		return w.WriteFloat32(f)
+	}
+
+	return w.head(float64Byte, math.Float64bits(v), 8)
+}
+
+// writeLen writes a header with the smallest format that fits n, where fix is the fixed
+// format (or 0 if there is none) and maxFix the largest length it can hold.
+func (w *Writer) writeLen(n int, fix byte, maxFix int, f8, f16, f32 byte) error {
+	switch {
+	case fix != 0 && n <= maxFix:
+		return w.w.WriteUint8(fix | byte(n))
+	case f8 != 0 && n <= math.MaxUint8:
+		return w.head(f8, uint64(n), 1)
+	case n <= math.MaxUint16:
+		return w.head(f16, uint64(n), 2)
+	case uint64(n) <= math.MaxUint32:
+		return w.head(f32, uint64(n), 4)
+	}
+
+	return fmt.Errorf("%w: %d", ErrTooLarge, n)
+}
+
+func (w *Writer) WriteString(s string) (err error) {
+	if err = w.writeLen(len(s), fixstr, 31, str8, str16, str32); err != nil {
+		return
+	}
+
+	_, err = w.w.WriteString(s)
+	return
+}
+
+func (w *Writer) WriteBytes(b []byte) (err error) {
+	if err = w.writeLen(len(b), 0, 0, bin8, bin16, bin32); err != nil {
+		return
+	}
+
+	return w.write(b)
+}
+
+// WriteArrayHeader writes the header of an array of n elements, which must follow.
+func (w *Writer) WriteArrayHeader(n int) error {
+	return w.writeLen(n, fixarray, 15, 0, array16, array32)
+}
+
+// WriteMapHeader writes the header of a map of n key-value pairs, which must follow.
+func (w *Writer) WriteMapHeader(n int) error {
+	return w.writeLen(n, fixmap, 15, 0, map16, map32)
+}
+
+// WriteExt writes an extension value of type typ.
+func (w *Writer) WriteExt(typ int8, data []byte) (err error) {
+	switch len(data) {
+	case 1:
+		err = w.w.WriteUint8(fixext1)
+	case 2:
+		err = w.w.WriteUint8(fixext2)
+	case 4:
+		err = w.w.WriteUint8(fixext4)
+	case 8:
+		err = w.w.WriteUint8(fixext8)
+	case 16:
+		err = w.w.WriteUint8(fixext16)
+	default:
+		err = w.writeLen(len(data), 0, 0, ext8, ext16, ext32)
+	}
+
+	if err != nil {
+		return
+	}
+
+	if err = w.w.WriteInt8(typ); err != nil {
+		return
+	}
+
+	return w.write(data)
+}
+
+// WriteTime writes t as a timestamp extension, in the smallest of the 32, 64 and 96 bit
+// formats that fits.
+func (w *Writer) WriteTime(t time.Time) error {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+
+	if uint64(sec)>>34 != 0 {
+		w.tmp[0], w.tmp[1], w.tmp[2] = ext8, 12, timestampExtByte
+		binary.BigEndian.PutUint32(w.tmp[3:], uint32(nsec))
+		binary.BigEndian.PutUint64(w.tmp[7:], uint64(sec))
+		return w.write(w.tmp[:15])
+	}
+
+	if v := nsec<<34 | uint64(sec); v>>32 != 0 {
+		w.tmp[0], w.tmp[1] = fixext8, timestampExtByte
+		binary.BigEndian.PutUint64(w.tmp[2:], v)
+		return w.write(w.tmp[:10])
+	}
+
+	w.tmp[0], w.tmp[1] = fixext4, timestampExtByte
+	binary.BigEndian.PutUint32(w.tmp[2:], uint32(sec))
+	return w.write(w.tmp[:6])
+}
+
+// WriteVal writes nil, a bool, integer, float, string, byte slice, time.Time, []any or
+// map[string]any.
+func (w *Writer) WriteVal(val any) (err error) {
+	switch v := val.(type) {
+
+	case nil:
+		return w.WriteNil()
+
+	case bool:
+		return w.WriteBool(v)
+
+	case int:
+		return w.WriteInt(int64(v))
+
+	case int8:
+		return w.WriteInt(int64(v))
+
+	case int16:
+		return w.WriteInt(int64(v))
+
+	case int32:
+		return w.WriteInt(int64(v))
+
+	case int64:
+		return w.WriteInt(v)
+
+	case uint:
+		return w.WriteUint(uint64(v))
+
+	case uint8:
+		return w.WriteUint(uint64(v))
+
+	case uint16:
+		return w.WriteUint(uint64(v))
+
+	case uint32:
+		return w.WriteUint(uint64(v))
+
+	case uint64:
+		return w.WriteUint(v)
+
+	case float32:
+		return w.WriteFloat32(v)
+
+	case float64:
+		return w.WriteFloat64(v)
+
+	case string:
+		return w.WriteString(v)
+
+	case []byte:
+		return w.WriteBytes(v)
+
+	case time.Time:
+		return w.WriteTime(v)
+
+	case []any:
+		if err = w.WriteArrayHeader(len(v)); err != nil {
+			return
+		}
+
+		for i := range v {
+			if err = w.WriteVal(v[i]); err != nil {
+				return
+			}
+		}
+
+		return
+
+	case map[string]any:
+		if err = w.WriteMapHeader(len(v)); err != nil {
+			return
+		}
+
+		for k, e := range v {
+			if err = w.WriteString(k); err != nil {
+				return
+			}
+
+			if err = w.WriteVal(e); err != nil {
+				return
+			}
+		}
+
+		return
+
+	}
+
+	return fmt.Errorf("%w: %T", fastbinary.ErrUnknownValue, val)
+}
@@ -1,11 +1,27 @@
 package fast
 
-import "sync"
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+)
 
 type Pool[T any] struct {
-	pool  sync.Pool
-	init  func(*T)
-	reset func(*T)
+	acquires atomic.Uint64
+	releases atomic.Uint64
+	news     atomic.Uint64
+
+	pool    sync.Pool
+	init    func(*T)
+	reset   func(*T)
+	tracker *Tracker[T]
+}
+
+// PoolStats holds the counters of a Pool since it was created.
+type PoolStats struct {
+	Acquires uint64 // Number of calls to Acquire
+	Releases uint64 // Number of calls to Release
+	News     uint64 // Number of items created because the pool was empty
 }
 
 // Created a new pool and accepts two (2) optional callbacks. The first is a initializer, and will be called
@@ -27,14 +43,21 @@ func NewPool[T any](cb ...func(*T)) *Pool[T] {
 
 // Acquires an item from the pool.
 func (p *Pool[T]) Acquire() *T {
-	if v, ok := p.pool.Get().(*T); ok {
-		return v
-	}
+	p.acquires.Add(1)
+
+	v, ok := p.pool.Get().(*T)
 
-	v := new(T)
+	if !ok {
+		p.news.Add(1)
+		v = new(T)
 
-	if p.init != nil {
-		p.init(v)
+		if p.init != nil {
+			p.init(v)
+		}
+	}
+
+	if p.tracker != nil {
+		p.tracker.Acquire(v)
 	}
 
 	return v
@@ -42,9 +65,46 @@ func (p *Pool[T]) Acquire() *T {
 
 // Releases an item back to the pool. The item cannot be used after release.
 func (p *Pool[T]) Release(v *T) {
+	p.releases.Add(1)
+
+	if p.tracker != nil && !p.tracker.Release(v) {
+		return
+	}
+
 	if p.reset != nil {
 		p.reset(v)
 	}
 
+	if p.tracker != nil {
+		var zero T
+		*v = zero
+		return
+	}
+
 	p.pool.Put(v)
 }
+
+// SetDebug enables debug mode, in which the pool tracks its items to detect items released
+// twice, not acquired from the pool or never released, and reports them to report (see
+// NewTracker). Released items are reset, then zeroed and never reused, so that any use after
+// release is noticed. It must be called before the pool is used.
+func (p *Pool[T]) SetDebug(report func(*PoolError)) {
+	p.tracker = NewTracker[T](report)
+}
+
+// Stats returns a snapshot of the pool's counters. The hit rate is 1 - News/Acquires.
+func (p *Pool[T]) Stats() PoolStats {
+	return PoolStats{
+		Acquires: p.acquires.Load(),
+		Releases: p.releases.Load(),
+		News:     p.news.Load(),
+	}
+}
+
+// Publish exports the pool's stats as an expvar variable with the given name. Like
+// expvar.Publish, it panics if the name is already in use.
+func (p *Pool[T]) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return p.Stats()
+	}))
+}
@@ -1,6 +1,7 @@
 package fast
 
 import (
+	"expvar"
 	"sync"
 	"testing"
 )
@@ -59,3 +60,26 @@ func BenchmarkGenericPool_Parallell(b *testing.B) {
 		}
 	})
 }
+
+func TestPool_Stats(t *testing.T) {
+	pool := NewPool[struct{ v int }]()
+	v := pool.Acquire()
+	pool.Release(v)
+	pool.Acquire()
+
+	s := pool.Stats()
+
+	if s.Acquires != 2 || s.Releases != 1 || s.News < 1 || s.News > 2 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+}
+
+func TestPool_Publish(t *testing.T) {
+	pool := NewPool[struct{ v int }]()
+	pool.Acquire()
+	pool.Publish("fast_test_pool")
+
+	if s := expvar.Get("fast_test_pool").String(); s != `{"Acquires":1,"Releases":0,"News":1}` {
+		t.Errorf("unexpected expvar value: %s", s)
+	}
+}
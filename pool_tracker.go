@@ -0,0 +1,152 @@
+package fast
+
+import (
+	"errors"
+	"log"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"unsafe"
+)
+
+var (
+	ErrDoubleRelease   = errors.New("item released twice")
+	ErrUseAfterRelease = errors.New("item used after release")
+	ErrLeak            = errors.New("item never released")
+	ErrForeign         = errors.New("item not acquired from the pool")
+)
+
+// A PoolError describes a misuse of a pooled item, detected by a Tracker.
+type PoolError struct {
+	Err   error  // ErrDoubleRelease, ErrUseAfterRelease, ErrLeak or ErrForeign
+	Stack []byte // Where the item was released, acquired if it leaked, or the foreign release
+}
+
+func (e *PoolError) Error() string {
+	return e.Err.Error() + "; see stack:\n" + string(e.Stack)
+}
+
+func (e *PoolError) Unwrap() error {
+	return e.Err
+}
+
+// A Tracker records where pooled items are acquired and released, in order to detect items
+// released twice, used after release, released without being acquired from the pool, or
+// never released at all. Leaks are detected when the item is garbage collected, so an item
+// that is part of a reference cycle is never reported.
+//
+// Capturing stacks is slow, so a Tracker is only meant for debugging. Zero-sized items can't
+// be told apart and aren't tracked.
+type Tracker[T any] struct {
+	report func(*PoolError)
+	mu     sync.Mutex
+	items  map[uintptr]*trackedItem
+}
+
+type trackedItem struct {
+	released bool
+	stack    []byte
+}
+
+// NewTracker creates a Tracker that calls report for every misuse. If report is nil, double
+// releases and uses after release panic, and leaks are logged.
+func NewTracker[T any](report func(*PoolError)) *Tracker[T] {
+	if report == nil {
+		report = defaultReport
+	}
+
+	return &Tracker[T]{
+		report: report,
+		items:  make(map[uintptr]*trackedItem),
+	}
+}
+
+func defaultReport(err *PoolError) {
+	if err.Err == ErrLeak {
+		log.Print(err)
+		return
+	}
+
+	panic(err)
+}
+
+// Acquire records that v was acquired from the pool. v must either have been released to
+// the Tracker, or have just been allocated by the pool itself: only then is it known to be
+// the start of an allocation, which can be garbage collected to detect a leak.
+func (t *Tracker[T]) Acquire(v *T) {
+	if unsafe.Sizeof(*v) == 0 {
+		return
+	}
+
+	key := uintptr(unsafe.Pointer(v))
+
+	t.mu.Lock()
+	_, known := t.items[key]
+	t.items[key] = &trackedItem{stack: debug.Stack()}
+	t.mu.Unlock()
+
+	if !known {
+		runtime.SetFinalizer(v, t.collected)
+	}
+}
+
+// Release records that v was released to the pool. If v has already been released, or
+// wasn't acquired from the pool (e.g. a pointer into another struct), the misuse is reported
+// and false is returned, in which case v must not be pooled.
+func (t *Tracker[T]) Release(v *T) bool {
+	if unsafe.Sizeof(*v) == 0 {
+		return true
+	}
+
+	key := uintptr(unsafe.Pointer(v))
+
+	t.mu.Lock()
+	item, ok := t.items[key]
+
+	if !ok || item.released {
+		t.mu.Unlock()
+
+		if ok {
+			t.report(&PoolError{Err: ErrDoubleRelease, Stack: item.stack})
+		} else {
+			t.report(&PoolError{Err: ErrForeign, Stack: debug.Stack()})
+		}
+
+		return false
+	}
+
+	t.items[key] = &trackedItem{released: true, stack: debug.Stack()}
+	t.mu.Unlock()
+	return true
+}
+
+// UsedAfterRelease reports that v was modified after it was released. It is meant for pools
+// that can verify their items, e.g. by poisoning released memory.
+func (t *Tracker[T]) UsedAfterRelease(v *T) {
+	t.mu.Lock()
+	item := t.items[uintptr(unsafe.Pointer(v))]
+	t.mu.Unlock()
+
+	var stack []byte
+
+	if item != nil {
+		stack = item.stack
+	}
+
+	t.report(&PoolError{Err: ErrUseAfterRelease, Stack: stack})
+}
+
+// collected removes a garbage collected item, so that its address can be reused by another
+// item, and reports it as leaked unless it was released.
+func (t *Tracker[T]) collected(v *T) {
+	key := uintptr(unsafe.Pointer(v))
+
+	t.mu.Lock()
+	item := t.items[key]
+	delete(t.items, key)
+	t.mu.Unlock()
+
+	if item != nil && !item.released {
+		t.report(&PoolError{Err: ErrLeak, Stack: item.stack})
+	}
+}
@@ -0,0 +1,88 @@
+package fast
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+type debugItem struct {
+	v []int
+}
+
+func TestPool_Debug(t *testing.T) {
+	reported := make(chan *PoolError, 4)
+	pool := NewPool[debugItem](func(i *debugItem) {
+		i.v = make([]int, 4)
+	})
+	pool.SetDebug(func(err *PoolError) {
+		reported <- err
+	})
+
+	v := pool.Acquire()
+	pool.Release(v)
+
+	if v.v != nil {
+		t.Errorf("expected a released item to be zeroed, got %v", v.v)
+	}
+
+	pool.Release(v)
+
+	if err := <-reported; !errors.Is(err, ErrDoubleRelease) {
+		t.Fatalf("expected a double release, got %v", err)
+	}
+
+	pool.Acquire()
+
+	for range 10 {
+		runtime.GC()
+
+		select {
+		case err := <-reported:
+			if !errors.Is(err, ErrLeak) {
+				t.Fatalf("expected a leak, got %v", err)
+			}
+
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	t.Error("expected a leak to be reported")
+}
+
+func TestPool_Debug_Reset(t *testing.T) {
+	var resets int
+	pool := NewPool[debugItem](nil, func(*debugItem) {
+		resets++
+	})
+	pool.SetDebug(func(*PoolError) {})
+
+	pool.Release(pool.Acquire())
+
+	if resets != 1 {
+		t.Errorf("expected the reset callback to be called once, got %d", resets)
+	}
+}
+
+func TestPool_Debug_Foreign(t *testing.T) {
+	var reported []*PoolError
+	pool := NewPool[debugItem]()
+	pool.SetDebug(func(err *PoolError) {
+		reported = append(reported, err)
+	})
+
+	// A pointer into another struct must be reported rather than crash the runtime when
+	// setting a finalizer.
+	var outer struct {
+		n    int
+		item debugItem
+	}
+
+	pool.Release(&outer.item)
+
+	if len(reported) != 1 || !errors.Is(reported[0], ErrForeign) {
+		t.Errorf("expected a foreign item, got %v", reported)
+	}
+}
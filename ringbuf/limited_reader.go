@@ -10,8 +10,9 @@ var _ RingBufferReader = (*LimitedReader)(nil)
 // that can be read. The underlying Reader and the remaining limit are stored in
 // unexported fields.
 type LimitedReader struct {
-	r *Reader // underlying Reader (unexported)
-	n int     // maximum remaining bytes allowed to be read (unexported)
+	r        *Reader // underlying Reader (unexported)
+	n        int     // maximum remaining bytes allowed to be read (unexported)
+	consumed int     // bytes read through the LimitedReader since the limit was set or flushed
 }
 
 func (r *LimitedReader) RingReader() *Reader {
@@ -32,12 +33,29 @@ func (r *LimitedReader) SetManualFlush(v bool) {
 
 func (r *LimitedReader) Flush() {
 	r.r.Flush()
+	r.consumed = 0
 }
 
+// Rewind rewinds the read data back to start, and restores the limit by the
+// number of rewound bytes that were read through the LimitedReader. Bytes read
+// before the limit was set don't count against it, and aren't restored.
 func (r *LimitedReader) Rewind() {
+	r.n += min(r.consumed, int(r.r.ring.read-r.r.ring.start))
+	r.consumed = 0
 	r.r.Rewind()
 }
 
+// consume counts n read bytes against the limit.
+func (lr *LimitedReader) consume(n int) {
+	lr.n -= n
+	lr.consumed += n
+}
+
+// Remaining returns the number of bytes left before the limit is reached.
+func (lr *LimitedReader) Remaining() int {
+	return max(lr.n, 0)
+}
+
 // Buffered returns the number of unread bytes currently buffered, capped to the remaining limit.
 func (lr *LimitedReader) Buffered() int {
 	buf := lr.r.Buffered()
@@ -57,7 +75,7 @@ func (lr *LimitedReader) Read(p []byte) (n int, err error) {
 		p = p[:lr.n]
 	}
 	n, err = lr.r.Read(p)
-	lr.n -= n
+	lr.consume(n)
 	return n, err
 }
 
@@ -68,7 +86,7 @@ func (lr *LimitedReader) ReadByte() (byte, error) {
 	}
 	b, err := lr.r.ReadByte()
 	if err == nil {
-		lr.n--
+		lr.consume(1)
 	}
 	return b, err
 }
@@ -95,7 +113,7 @@ func (lr *LimitedReader) ReadBytes(n int) ([]byte, error) {
 		n = lr.n
 	}
 	b, err := lr.r.ReadBytes(n)
-	lr.n -= len(b)
+	lr.consume(len(b))
 	return b, err
 }
 
@@ -108,7 +126,7 @@ func (lr *LimitedReader) Discard(n int) (discarded int, err error) {
 		n = lr.n
 	}
 	discarded, err = lr.r.Discard(n)
-	lr.n -= discarded
+	lr.consume(discarded)
 	return discarded, err
 }
 
@@ -123,6 +141,6 @@ func (lr *LimitedReader) DiscardUntil(c byte) (discarded int, err error) {
 	if discarded > lr.n {
 		discarded = lr.n
 	}
-	lr.n -= discarded
+	lr.consume(discarded)
 	return discarded, err
 }
@@ -225,3 +225,29 @@ func TestLimitedReaderLimitExhaustion(t *testing.T) {
 		t.Fatalf("After limit reached: expected 0 bytes, got %d", n)
 	}
 }
+
+func TestLimitedReaderRewind(t *testing.T) {
+	r := NewReader(strings.NewReader("0123456789"))
+	r.SetManualFlush(true)
+
+	// Bytes read before the limit is set must not be restored by Rewind.
+	if _, err := r.ReadBytes(4); err != nil {
+		t.Fatal(err)
+	}
+
+	lr := r.LimitReader(3)
+
+	if b, err := lr.ReadBytes(3); err != nil || string(b) != "456" {
+		t.Fatalf("ReadBytes: got %q, %v", b, err)
+	}
+
+	lr.Rewind()
+
+	if lr.n != 3 {
+		t.Fatalf("expected the limit to be restored to 3, got %d", lr.n)
+	}
+
+	if b, err := lr.ReadBytes(10); err != nil || string(b) != "012" {
+		t.Fatalf("ReadBytes after Rewind: got %q, %v", b, err)
+	}
+}
@@ -168,7 +168,7 @@ func (r *Reader) Peek(n int) ([]byte, error) {
 // It fills the buffer as needed; if fewer than n bytes are available, it returns io.EOF.
 func (r *Reader) ReadBytes(n int) (b []byte, err error) {
 	b, err = r.Peek(n)
-	r.ring.read += uint64(len(b))
+	r.ring.advance(uint64(len(b)))
 	return
 }
 
@@ -196,7 +196,7 @@ func (r *Reader) Discard(n int) (discarded int, err error) {
 		if toDiscard > avail {
 			toDiscard = avail
 		}
-		r.ring.read += uint64(toDiscard)
+		r.ring.advance(uint64(toDiscard))
 		total += toDiscard
 		n -= toDiscard
 	}
@@ -231,12 +231,12 @@ func (r *Reader) DiscardUntil(c byte) (discarded int, err error) {
 		index := bytes.IndexByte(buf, c)
 		if index >= 0 {
 			// Found c: discard up to that position.
-			r.ring.read += uint64(index)
+			r.ring.advance(uint64(index))
 			total += index
 			return total, nil
 		}
 		// c not found in current buffer: discard all and continue.
-		r.ring.read += uint64(avail)
+		r.ring.advance(uint64(avail))
 		total += avail
 	}
 }
@@ -251,6 +251,7 @@ func (r *Reader) LimitReader(n int) *LimitedReader {
 		}
 	} else {
 		r.limited.n = n
+		r.limited.consumed = 0
 	}
 	return r.limited
 }